@@ -0,0 +1,405 @@
+package sopt
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Runner is implemented by command structs registered through Bind. Run is called when the command is
+// selected on the command line.
+type Runner interface {
+	Run(ctx context.Context, opt *Options, args []string) error
+}
+
+// binding connects an option to the struct field receiving its value.
+type binding struct {
+	o     *Option
+	field reflect.Value
+}
+
+// Bind registers options, positional arguments, groups and commands from the tagged fields of the struct
+// v points to. After ParseArgs, the parsed values (or defaults) are written to the fields.
+//
+// Supported tags:
+//   - sopt:"p,port" names an option by its short and/or long name.
+//   - positional:"FILE" makes the field a positional argument.
+//   - command:"name" makes a struct (or struct pointer) field a command with its own bound options.
+//     Commands implementing Runner are run with it.
+//   - help, default, group, choices (comma-separated), required:"true", env, placeholder, sep
+//     (the Separator of slices) and count:"true" (for counter ints) describe options. Commands also take help, group and aliases.
+//   - min and max set the arity of positional slices (see SetArity), and secret:"true" sets Secret.
+//
+// Untagged struct fields are bound as groups named by their group tag or their field name.
+// Supported field types are bool, ints, floats, string, []string, time.Duration, Size, time.Time,
+// *url.URL and any type whose pointer implements Value. Slices become repeatable options or positional
+// slices.
+func (opt *Options) Bind(v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.Elem().Kind() != reflect.Struct {
+		return ErrBindTarget
+	}
+
+	return opt.bindStruct(rv.Elem(), "")
+}
+
+// bindStruct registers the fields of a struct into a group.
+func (opt *Options) bindStruct(rv reflect.Value, group string) error {
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		f := rt.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+
+		fv := rv.Field(i)
+		name, ok := f.Tag.Lookup("command")
+		if ok {
+			err := opt.bindCommand(f, fv, name, group)
+			if err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		placeholder, ok := f.Tag.Lookup("positional")
+		if ok {
+			err := opt.bindPositional(f, fv, placeholder)
+			if err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		names, ok := f.Tag.Lookup("sopt")
+		if !ok {
+			if f.Type.Kind() == reflect.Struct {
+				g := f.Tag.Get("group")
+				if g == "" {
+					g = f.Name
+				}
+
+				err := opt.bindStruct(fv, g)
+				if err != nil {
+					return err
+				}
+			}
+
+			continue
+		}
+
+		err := opt.bindOption(f, fv, names, group)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// bindOption registers a field as an option.
+func (opt *Options) bindOption(f reflect.StructField, fv reflect.Value, names, group string) error {
+	short, long := "", ""
+	for _, n := range strings.Split(names, ",") {
+		n = strings.TrimSpace(n)
+		if len(n) == 1 {
+			short = n
+		} else {
+			long = n
+		}
+	}
+
+	if g := f.Tag.Get("group"); g != "" {
+		group = g
+	}
+
+	v, ok := fieldValue(fv)
+	if ok {
+		if def, ok := f.Tag.Lookup("default"); ok {
+			err := v.Set(def)
+			if err != nil {
+				return fmt.Errorf("%s: %w", f.Name, err)
+			}
+		}
+
+		err := opt.SetValue(group, short, long, f.Tag.Get("help"), v, f.Tag.Get("required") == "true", tagChoices(f))
+		if err != nil {
+			return err
+		}
+
+		o := opt.long[long]
+		if o == nil {
+			o = opt.short[short]
+		}
+
+		o.Env = f.Tag.Get("env")
+		o.Secret = f.Tag.Get("secret") == "true"
+		if p := f.Tag.Get("placeholder"); p != "" {
+			o.Placeholder = p
+		}
+
+		return nil
+	}
+
+	t, ok := bindType(f.Type)
+	if !ok {
+		return fmt.Errorf("%s: %w", f.Name, ErrUnknownType)
+	}
+
+	if t == VarTypeInt && f.Tag.Get("count") == "true" {
+		t = VarTypeCount
+	}
+
+	o := &Option{Type: t}
+	choices, err := bindChoices(o, f.Tag.Get("choices"))
+	if err != nil {
+		return fmt.Errorf("%s: %w", f.Name, err)
+	}
+
+	def, err := bindDefault(o, f, fv)
+	if err != nil {
+		return fmt.Errorf("%s: %w", f.Name, err)
+	}
+
+	err = opt.SetOption(group, short, long, f.Tag.Get("help"), def, f.Tag.Get("required") == "true", t, choices)
+	if err != nil {
+		return err
+	}
+
+	o = opt.long[long]
+	if o == nil {
+		o = opt.short[short]
+	}
+
+	o.Env = f.Tag.Get("env")
+	o.Secret = f.Tag.Get("secret") == "true"
+	o.Placeholder = f.Tag.Get("placeholder")
+	o.Separator = f.Tag.Get("sep")
+	opt.binds = append(opt.binds, binding{o: o, field: fv})
+	return nil
+}
+
+// bindPositional registers a field as a positional argument.
+func (opt *Options) bindPositional(f reflect.StructField, fv reflect.Value, placeholder string) error {
+	v, ok := fieldValue(fv)
+	if ok {
+		err := opt.SetPosValue(placeholder, f.Tag.Get("help"), v, f.Tag.Get("required") == "true")
+		if err != nil {
+			return err
+		}
+
+		opt.posmap[placeholder].Choices = tagChoices(f)
+		return nil
+	}
+
+	t, ok := bindType(f.Type)
+	if !ok {
+		return fmt.Errorf("%s: %w", f.Name, ErrUnknownType)
+	}
+
+	if t == VarTypeStringSlice {
+		t = VarTypePosStringSlice
+	}
+
+	o := &Option{Type: t}
+	choices, err := bindChoices(o, f.Tag.Get("choices"))
+	if err != nil {
+		return fmt.Errorf("%s: %w", f.Name, err)
+	}
+
+	def, err := bindDefault(o, f, fv)
+	if err != nil {
+		return fmt.Errorf("%s: %w", f.Name, err)
+	}
+
+	err = opt.SetPositional(placeholder, f.Tag.Get("help"), def, f.Tag.Get("required") == "true", t)
+	if err != nil {
+		return err
+	}
+
+	o = opt.posmap[placeholder]
+	o.Choices = choices
+	o.Env = f.Tag.Get("env")
+	if f.Tag.Get("min") != "" || f.Tag.Get("max") != "" {
+		min, err1 := tagInt(f, "min")
+		max, err2 := tagInt(f, "max")
+		if err1 != nil || err2 != nil {
+			return fmt.Errorf("%s: %w", f.Name, ErrArity)
+		}
+
+		err = opt.SetArity(placeholder, min, max)
+		if err != nil {
+			return err
+		}
+	}
+
+	opt.binds = append(opt.binds, binding{o: o, field: fv})
+	return nil
+}
+
+// tagInt returns the integer in a struct tag, or 0 if it's not set.
+func tagInt(f reflect.StructField, key string) (int, error) {
+	s := f.Tag.Get(key)
+	if s == "" {
+		return 0, nil
+	}
+
+	return strconv.Atoi(s)
+}
+
+// bindCommand registers a struct field as a command and binds its fields to the command's options.
+func (opt *Options) bindCommand(f reflect.StructField, fv reflect.Value, name, group string) error {
+	if f.Type.Kind() == reflect.Ptr && f.Type.Elem().Kind() == reflect.Struct {
+		if fv.IsNil() {
+			fv.Set(reflect.New(f.Type.Elem()))
+		}
+	} else if f.Type.Kind() == reflect.Struct {
+		fv = fv.Addr()
+	} else {
+		return fmt.Errorf("%s: %w", f.Name, ErrBindTarget)
+	}
+
+	if g := f.Tag.Get("group"); g != "" {
+		group = g
+	}
+
+	var aliases []string
+	if a := f.Tag.Get("aliases"); a != "" {
+		aliases = strings.Split(a, ",")
+	}
+
+	cmd := opt.SetCommand(name, f.Tag.Get("help"), group, nil, aliases)
+	r, ok := fv.Interface().(Runner)
+	if ok {
+		cmd.FuncCtx = r.Run
+	}
+
+	return cmd.Options.bindStruct(fv.Elem(), "")
+}
+
+// fieldValue returns the field as a Value if its address implements it.
+func fieldValue(fv reflect.Value) (Value, bool) {
+	if !fv.CanAddr() {
+		return nil, false
+	}
+
+	v, ok := fv.Addr().Interface().(Value)
+	return v, ok
+}
+
+// tagChoices returns the comma-separated choices tag as strings.
+func tagChoices(f reflect.StructField) []any {
+	s := f.Tag.Get("choices")
+	if s == "" {
+		return nil
+	}
+
+	var choices []any
+	for _, c := range strings.Split(s, ",") {
+		choices = append(choices, strings.TrimSpace(c))
+	}
+
+	return choices
+}
+
+// bindType returns the option type for a field type.
+func bindType(t reflect.Type) (uint8, bool) {
+	switch t {
+	case reflect.TypeOf(time.Duration(0)):
+		return VarTypeDuration, true
+	case reflect.TypeOf(Size(0)):
+		return VarTypeSize, true
+	case reflect.TypeOf(time.Time{}):
+		return VarTypeTime, true
+	case reflect.TypeOf(&url.URL{}):
+		return VarTypeURL, true
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return VarTypeBool, true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return VarTypeInt, true
+	case reflect.Float32, reflect.Float64:
+		return VarTypeFloat, true
+	case reflect.String:
+		return VarTypeString, true
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.String {
+			return VarTypeStringSlice, true
+		}
+	}
+
+	return 0, false
+}
+
+// bindChoices converts comma-separated choices to the option's type.
+func bindChoices(o *Option, s string) ([]any, error) {
+	if s == "" {
+		return nil, nil
+	}
+
+	choices := []any{}
+	for _, c := range strings.Split(s, ",") {
+		v, err := o.parseValue(strings.TrimSpace(c))
+		if err != nil {
+			return nil, err
+		}
+
+		choices = append(choices, v)
+	}
+
+	return choices, nil
+}
+
+// bindDefault returns the default tag converted to the option's type, or the field's value if it
+// has no default tag and isn't the zero value.
+func bindDefault(o *Option, f reflect.StructField, fv reflect.Value) (any, error) {
+	s, ok := f.Tag.Lookup("default")
+	if ok {
+		if o.Type == VarTypeStringSlice || o.Type == VarTypePosStringSlice {
+			return strings.Split(s, ","), nil
+		}
+
+		return o.parseValue(s)
+	}
+
+	if fv.IsZero() {
+		return nil, nil
+	}
+
+	switch o.Type {
+	case VarTypeBool:
+		return fv.Bool(), nil
+	case VarTypeInt, VarTypeCount:
+		return int(fv.Int()), nil
+	case VarTypeFloat:
+		return fv.Float(), nil
+	case VarTypeString:
+		return fv.String(), nil
+	case VarTypeDuration, VarTypeSize, VarTypeTime, VarTypeURL:
+		return fv.Interface(), nil
+	}
+
+	return append([]string{}, fv.Interface().([]string)...), nil
+}
+
+// applyBinds writes the values of bound options to their fields, and zero values for unset ones without a
+// default, so nothing is left from an earlier parse.
+func (opt *Options) applyBinds(r *Result) {
+	for _, b := range opt.binds {
+		v := r.get(b.o)
+		if v == nil {
+			b.field.Set(reflect.Zero(b.field.Type()))
+			continue
+		}
+
+		b.field.Set(reflect.ValueOf(v).Convert(b.field.Type()))
+	}
+}
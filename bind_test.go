@@ -0,0 +1,105 @@
+package sopt_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/grimdork/sopt"
+)
+
+type addCmd struct {
+	Name string `sopt:"n,name" help:"Remote name." required:"true"`
+	URL  string `positional:"URL" help:"Remote URL."`
+	ran  bool
+}
+
+func (c *addCmd) Run(ctx context.Context, opt *sopt.Options, args []string) error {
+	c.ran = true
+	return nil
+}
+
+type config struct {
+	Verbose bool     `sopt:"v,verbose" help:"Show more details in output."`
+	Format  string   `sopt:"f,format" help:"Output format." default:"json" choices:"json,yaml"`
+	Include []string `sopt:"I,include" help:"Include path."`
+	Network struct {
+		Port    int     `sopt:"p,port" help:"Port number." default:"8080"`
+		Timeout float64 `sopt:"timeout" help:"Timeout in seconds."`
+	} `group:"Network"`
+	Add *addCmd `command:"add" help:"Add a remote." aliases:"a"`
+}
+
+func TestBind(t *testing.T) {
+	var cfg config
+	cfg.Network.Timeout = 2.5
+	opt := sopt.New()
+	err := opt.Bind(&cfg)
+	if err != nil {
+		t.Errorf("Expected no error, but got %s", err.Error())
+		t.FailNow()
+	}
+
+	opt.PrintHelp()
+	args := []string{"-v", "-I", "a", "--include", "b", "--format=yaml", "a", "--name", "origin", "https://example.com/x"}
+	err = opt.ParseArgs(args)
+	if err != nil {
+		t.Errorf("Expected no error, but got %s", err.Error())
+		t.FailNow()
+	}
+
+	if !cfg.Verbose || cfg.Format != "yaml" || len(cfg.Include) != 2 {
+		t.Errorf("Expected parsed values, but got %+v", cfg)
+		t.Fail()
+	}
+
+	if cfg.Network.Port != 8080 || cfg.Network.Timeout != 2.5 {
+		t.Errorf("Expected defaults, but got %+v", cfg.Network)
+		t.Fail()
+	}
+
+	if opt.GetGroup("Network") == nil {
+		t.Errorf("Expected group 'Network' to exist, but it does not.")
+		t.Fail()
+	}
+
+	if !cfg.Add.ran || cfg.Add.Name != "origin" || cfg.Add.URL != "https://example.com/x" {
+		t.Errorf("Expected command to run with its values, but got %+v", cfg.Add)
+		t.Fail()
+	}
+}
+
+func TestBindReuse(t *testing.T) {
+	var cfg config
+	opt := sopt.New()
+	err := opt.Bind(&cfg)
+	if err != nil {
+		t.Errorf("Expected no error, but got %s", err.Error())
+		t.FailNow()
+	}
+
+	err = opt.ParseArgs([]string{"-v", "-I", "a", "--port", "9000"})
+	if err != nil {
+		t.Errorf("Expected no error, but got %s", err.Error())
+		t.FailNow()
+	}
+
+	err = opt.ParseArgs([]string{})
+	if err != nil {
+		t.Errorf("Expected no error, but got %s", err.Error())
+		t.FailNow()
+	}
+
+	if cfg.Verbose || cfg.Include != nil || cfg.Network.Port != 8080 || cfg.Format != "json" {
+		t.Errorf("Expected defaults and zero values after the second parse, but got %+v", cfg)
+		t.Fail()
+	}
+}
+
+func TestBindTarget(t *testing.T) {
+	var cfg config
+	err := sopt.New().Bind(cfg)
+	if err != sopt.ErrBindTarget {
+		t.Errorf("Expected ErrBindTarget, but got %v", err)
+		t.Fail()
+	}
+}
@@ -1,5 +1,7 @@
 package sopt
 
+import "context"
+
 // Command definition.
 type Command struct {
 	// Name of the command.
@@ -8,29 +10,110 @@ type Command struct {
 	Help string
 	// Func to execute the command.
 	Func ToolCommand
-	// Options for this command.
-	Options []*Option
+	// FuncCtx to execute the command with a context and the parsed options. Used instead of Func if set.
+	FuncCtx ToolCommandCtx
+	// Options for this command. Options, positional arguments, groups and sub-commands
+	// defined here are parsed from the arguments following the command name.
+	Options *Options
 	// Aliases for this command.
 	Aliases []string
+	// RawArgs passes the arguments following the command name to it as they are, without parsing them for
+	// the command or its parents.
+	RawArgs bool
+	// Complete returns completion candidates for the command's arguments when it has no positional
+	// argument to complete.
+	Complete CompleteFunc
 }
 
 // ToolCommand function signature.
 type ToolCommand func(args []string) error
 
+// ToolCommandCtx function signature. The context is cancelled on SIGINT or SIGTERM when parsing through
+// Parse, and carries the Result of the parse for ResultFrom. After ParseArgs, opt is the command's own
+// Options, which also gives access to the options of its parents. After ParseResult, opt is nil, since
+// the getters of Options don't see its values; read them from ResultFrom(ctx) instead.
+type ToolCommandCtx func(ctx context.Context, opt *Options, args []string) error
+
 // SetCommand to a group.
 func (opt *Options) SetCommand(name, help, group string, fn ToolCommand, aliases []string) *Command {
 	cmd := &Command{
 		Name:    name,
 		Help:    help,
 		Func:    fn,
+		Options: New(),
+		Aliases: aliases,
+	}
+
+	opt.addCommand(cmd, group)
+	return cmd
+}
+
+// SetCommandCtx to a group. The function receives a context and the command's parsed options, as described
+// for ToolCommandCtx.
+func (opt *Options) SetCommandCtx(name, help, group string, fn ToolCommandCtx, aliases []string) *Command {
+	cmd := &Command{
+		Name:    name,
+		Help:    help,
+		FuncCtx: fn,
+		Options: New(),
 		Aliases: aliases,
 	}
 
-	opt.commands[name] = cmd
+	opt.addCommand(cmd, group)
+	return cmd
+}
+
+// addCommand links a command's options to these and adds it to a group.
+func (opt *Options) addCommand(cmd *Command, group string) {
+	cmd.Options.parent = opt
+	cmd.Options.name = cmd.Name
+	opt.commands[cmd.Name] = cmd
 	g := opt.GetGroup(group)
 	if g == nil {
 		g = opt.AddGroup(group)
 	}
 	g.commands = append(g.commands, cmd.Name)
-	return cmd
+}
+
+// Path returns the names of the commands leading to and including this one, separated by spaces.
+func (cmd *Command) Path() string {
+	return cmd.Options.commandPath()
+}
+
+// stopKey is the context key of the function releasing the signals caught by Parse.
+type stopKey struct{}
+
+// run calls the command function. Func can't see the context, so the signals caught for it are released first.
+func (cmd *Command) run(ctx context.Context, opt *Options, args []string) error {
+	if cmd.FuncCtx != nil {
+		return cmd.FuncCtx(ctx, opt, args)
+	}
+
+	if cmd.Func != nil {
+		if stop, ok := ctx.Value(stopKey{}).(context.CancelFunc); ok {
+			stop()
+		}
+
+		return cmd.Func(args)
+	}
+
+	return ErrMissingFunc
+}
+
+// GetCommand returns a pointer to a command, looked up by name or alias.
+func (opt *Options) GetCommand(name string) *Command {
+	cmd, ok := opt.commands[name]
+	if ok {
+		return cmd
+	}
+
+	for _, cmd := range opt.commands {
+		for _, alias := range cmd.Aliases {
+			if alias == name {
+				return cmd
+			}
+		}
+	}
+
+	return nil
 }
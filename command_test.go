@@ -0,0 +1,148 @@
+package sopt_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/grimdork/sopt"
+)
+
+func TestNestedCommand(t *testing.T) {
+	opt := sopt.New()
+	opt.SetOption("", "v", "verbose", "Show more details in output.", false, false, sopt.VarTypeBool, nil)
+	remote := opt.SetCommand("remote", "Manage remotes.", "", nil, nil)
+	var name string
+	var rest []string
+	var verbose bool
+	add := remote.Options.SetCommand("add", "Add a remote.", "", func(args []string) error {
+		rest = args
+		return nil
+	}, []string{"a"})
+	add.Options.SetOption("", "n", "name", "Remote name.", nil, true, sopt.VarTypeString, nil)
+	add.Options.SetPositional("URL", "Remote URL.", nil, false, sopt.VarTypeString)
+
+	args := []string{"remote", "a", "--name", "origin", "-v", "https://example.com/x"}
+	err := opt.ParseArgs(args)
+	if err != nil {
+		t.Errorf("Expected no error, but got %s", err.Error())
+		t.FailNow()
+	}
+
+	name = add.Options.GetString("name")
+	verbose = add.Options.GetBool("verbose")
+	if name != "origin" {
+		t.Errorf("Expected 'origin', but got %s", name)
+		t.Fail()
+	}
+
+	if !verbose || !opt.GetBool("v") {
+		t.Errorf("Expected verbose to be true in both parent and leaf, but got false.")
+		t.Fail()
+	}
+
+	if add.Options.GetPosString("URL") != "https://example.com/x" {
+		t.Errorf("Expected URL to be set, but got %s", add.Options.GetPosString("URL"))
+		t.Fail()
+	}
+
+	if len(rest) != 0 {
+		t.Errorf("Expected no remaining arguments, but got %+v", rest)
+		t.Fail()
+	}
+}
+
+func TestNestedRequired(t *testing.T) {
+	opt := sopt.New()
+	remote := opt.SetCommand("remote", "Manage remotes.", "", nil, nil)
+	add := remote.Options.SetCommand("add", "Add a remote.", "", func(args []string) error {
+		t.Errorf("Command should not run without required options.")
+		return nil
+	}, nil)
+	add.Options.SetOption("", "n", "name", "Remote name.", nil, true, sopt.VarTypeString, nil)
+
+	err := opt.ParseArgs([]string{"remote", "add"})
+	if err == nil {
+		t.Errorf("Expected error, but missing required option worked.")
+		t.Fail()
+	} else {
+		t.Logf("Missing required option failed as expected: %s", err.Error())
+	}
+}
+
+var errFailed = errors.New("failed")
+
+type ctxKey struct{}
+
+func TestCommandError(t *testing.T) {
+	opt := sopt.New()
+	remote := opt.SetCommand("remote", "Manage remotes.", "", nil, nil)
+	remote.Options.SetCommand("rm", "Remove a remote.", "", func(args []string) error {
+		return errFailed
+	}, nil)
+
+	err := opt.ParseArgs([]string{"remote", "rm", "origin"})
+	if !errors.Is(err, errFailed) {
+		t.Errorf("Expected command error, but got %v", err)
+		t.FailNow()
+	}
+
+	if err.Error() != "remote rm: failed" {
+		t.Errorf("Expected error prefixed with command path, but got %s", err.Error())
+		t.Fail()
+	}
+}
+
+func TestCommandCtx(t *testing.T) {
+	opt := sopt.New()
+	opt.SetOption("", "v", "verbose", "Show more details in output.", false, false, sopt.VarTypeBool, nil)
+	ran := false
+	opt.SetCommandCtx("moo", "Have you mooed today?", "", func(ctx context.Context, o *sopt.Options, args []string) error {
+		ran = true
+		if ctx.Value(ctxKey{}) != "yes" {
+			t.Errorf("Expected context to be passed on.")
+		}
+
+		if !o.GetBool("verbose") {
+			t.Errorf("Expected parent option to be readable from command.")
+		}
+
+		if len(args) != 1 || args[0] != "loud" {
+			t.Errorf("Expected args [loud], but got %+v", args)
+		}
+
+		return nil
+	}, nil)
+
+	ctx := context.WithValue(context.Background(), ctxKey{}, "yes")
+	err := opt.ParseArgsContext(ctx, []string{"-v", "moo", "loud"})
+	if err != nil {
+		t.Errorf("Expected no error, but got %s", err.Error())
+		t.Fail()
+	}
+
+	if !ran {
+		t.Errorf("Expected command to run.")
+		t.Fail()
+	}
+}
+
+func TestCommandInheritedFlags(t *testing.T) {
+	var got []string
+	opt := sopt.New()
+	opt.SetOption("", "v", "verbose", "Verbose.", false, false, sopt.VarTypeBool, nil)
+	opt.SetCommand("run", "Run.", "", func(args []string) error { got = args; return nil }, nil)
+	raw := opt.SetCommand("exec", "Exec.", "", func(args []string) error { got = args; return nil }, nil)
+	raw.RawArgs = true
+	err := opt.ParseArgs([]string{"run", "-v", "x", "--force"})
+	if err != nil || !opt.GetBool("v") || len(got) != 2 || got[0] != "x" || got[1] != "--force" {
+		t.Errorf("Expected -v parsed and [x --force] passed on, but got %v, %v and %v", err, opt.GetBool("v"), got)
+		t.Fail()
+	}
+
+	err = opt.ParseArgs([]string{"exec", "-v", "x"})
+	if err != nil || opt.GetBool("v") || len(got) != 2 || got[0] != "-v" {
+		t.Errorf("Expected raw arguments [-v x], but got %v, %v and %v", err, opt.GetBool("v"), got)
+		t.Fail()
+	}
+}
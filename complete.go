@@ -0,0 +1,281 @@
+package sopt
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// CompleteFunc returns completion candidates for the word being completed. Args are the words before it.
+// Candidates may have a description after a tab character.
+type CompleteFunc func(args []string, word string) []string
+
+// completeCommand is the hidden command shells call to complete a command line.
+const completeCommand = "__complete"
+
+// EnableCompletion makes ParseArgs answer the hidden "__complete" command used by the scripts from
+// WriteCompletion. It prints the candidates for the last argument, one per line, instead of parsing.
+func (opt *Options) EnableCompletion() {
+	opt.completion = true
+}
+
+// WriteCompletion writes a completion script for "bash", "zsh" or "fish". The scripts call the program
+// with the hidden "__complete" command, so EnableCompletion must be called before parsing.
+func (opt *Options) WriteCompletion(w io.Writer, shell string) error {
+	name := opt.usageName()
+	fn := "_" + strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' {
+			return r
+		}
+
+		return '_'
+	}, name)
+
+	var script string
+	switch shell {
+	case "bash":
+		script = bashCompletion
+	case "zsh":
+		script = zshCompletion
+	case "fish":
+		script = fishCompletion
+	default:
+		return fmt.Errorf("%s: %w", shell, ErrUnknownShell)
+	}
+
+	script = strings.ReplaceAll(script, "{{func}}", fn)
+	script = strings.ReplaceAll(script, "{{name}}", name)
+	_, err := io.WriteString(w, script)
+	return err
+}
+
+const bashCompletion = `# bash completion for {{name}}
+{{func}}() {
+	local IFS=$'\n'
+	local cur="${COMP_WORDS[COMP_CWORD]}"
+	local words=("${COMP_WORDS[@]:1:COMP_CWORD-1}")
+	COMPREPLY=($({{name}} __complete "${words[@]}" "$cur" 2>/dev/null | cut -f1))
+	if [ ${#COMPREPLY[@]} -eq 0 ]; then
+		COMPREPLY=($(compgen -f -- "$cur"))
+	fi
+}
+complete -F {{func}} {{name}}
+`
+
+const zshCompletion = `#compdef {{name}}
+compdef {{func}} {{name}}
+
+{{func}}() {
+	local -a lines completions
+	local line word
+	lines=("${(@f)$({{name}} __complete "${(@)words[2,CURRENT-1]}" "${words[CURRENT]}" 2>/dev/null)}")
+	for line in "${lines[@]}"; do
+		[[ -z "$line" ]] && continue
+		word="${line%%$'\t'*}"
+		word="${word//:/\\:}"
+		if [[ "$line" == *$'\t'* ]]; then
+			completions+=("$word:${line#*$'\t'}")
+		else
+			completions+=("$word")
+		fi
+	done
+
+	if (( ${#completions} )); then
+		_describe '{{name}}' completions
+	else
+		_files
+	fi
+}
+
+if [ "$funcstack[1]" = "{{func}}" ]; then
+	{{func}} "$@"
+fi
+`
+
+const fishCompletion = `# fish completion for {{name}}
+function {{func}}
+	set -l tokens (commandline -opc)
+	set -e tokens[1]
+	{{name}} __complete $tokens (commandline -ct) 2>/dev/null
+end
+
+complete -c {{name}} -f -a '({{func}})'
+complete -c {{name}} -n 'test (count ({{func}})) -eq 0' -F
+`
+
+// printCompletion prints the candidates for the last of args.
+func (opt *Options) printCompletion(args []string) {
+	for _, c := range opt.Complete(args) {
+		fmt.Fprintln(opt.getOutput(), c)
+	}
+}
+
+// Complete returns the completion candidates for the last of args, given the words before it.
+// Candidates are options, commands and aliases, choices, and the results of CompleteFunc callbacks
+// on options and commands. Descriptions follow a tab character.
+func (opt *Options) Complete(args []string) []string {
+	if len(args) == 0 {
+		args = []string{""}
+	}
+
+	words, cur := args[:len(args)-1], args[len(args)-1]
+	level := opt
+	var cmd *Command
+	var expecting *Option
+	pos := 0
+	stop := false
+	for _, w := range words {
+		switch {
+		case stop:
+			if pos < len(level.positional) && level.positional[pos].Type != VarTypePosStringSlice {
+				pos++
+			}
+
+		case w == "--":
+			stop = true
+
+		case w == "=":
+			// Bash splits "--option=value" into three words.
+			continue
+
+		case expecting != nil:
+			expecting = nil
+
+		case strings.HasPrefix(w, "--") && len(w) > 2:
+			a := splitOption(w[2:])
+			o := level.lookupLong(a[0])
+			if o != nil && o.takesArg() && !strings.Contains(w, "=") {
+				expecting = o
+			}
+
+		case strings.HasPrefix(w, "-") && len(w) > 1 && w[1] != '=':
+			a := splitOption(w[1:])
+			o := level.lookupShort(a[0][len(a[0])-1:])
+			if o != nil && o.takesArg() && !strings.Contains(w, "=") {
+				expecting = o
+			}
+
+		default:
+			c := level.GetCommand(w)
+			if c != nil {
+				cmd = c
+				level = c.Options
+				pos = 0
+				continue
+			}
+
+			if pos < len(level.positional) && level.positional[pos].Type != VarTypePosStringSlice {
+				pos++
+			}
+		}
+	}
+
+	if cur == "=" {
+		cur = ""
+	}
+
+	if expecting != nil {
+		return filterCandidates(optionCandidates(expecting, words, cur), cur)
+	}
+
+	if strings.HasPrefix(cur, "--") && strings.Contains(cur, "=") && !stop {
+		a := splitOption(cur[2:])
+		o := level.lookupLong(a[0])
+		if o == nil {
+			return nil
+		}
+
+		list := []string{}
+		for _, c := range filterCandidates(optionCandidates(o, words, a[1]), a[1]) {
+			list = append(list, "--"+a[0]+"="+c)
+		}
+
+		return list
+	}
+
+	if strings.HasPrefix(cur, "-") && !stop {
+		return filterCandidates(level.optionNames(), cur)
+	}
+
+	list := []string{}
+	for _, name := range level.commandNames() {
+		if stop {
+			break
+		}
+
+		c := level.commands[name]
+		list = append(list, c.Name+"\t"+c.Help)
+		for _, alias := range c.Aliases {
+			list = append(list, alias+"\t"+c.Help)
+		}
+	}
+
+	if pos < len(level.positional) {
+		list = append(list, optionCandidates(level.positional[pos], words, cur)...)
+	} else if cmd != nil && cmd.Complete != nil {
+		list = append(list, cmd.Complete(words, cur)...)
+	}
+
+	return filterCandidates(list, cur)
+}
+
+// optionCandidates returns the choices of an option, or the results of its callback.
+func optionCandidates(o *Option, words []string, cur string) []string {
+	if o.Complete != nil {
+		return o.Complete(words, cur)
+	}
+
+	list := []string{}
+	for _, c := range o.Choices {
+		list = append(list, fmt.Sprint(c))
+	}
+
+	return list
+}
+
+// optionNames returns the long and short option names available at this level and its parents,
+// in group order.
+func (opt *Options) optionNames() []string {
+	list := []string{}
+	for p := opt; p != nil; p = p.parent {
+		for _, g := range p.GetGroups() {
+			for _, o := range g.options {
+				if o.LongName != "" {
+					list = append(list, "--"+o.LongName+"\t"+o.Help)
+				}
+
+				if o.negatable() {
+					list = append(list, "--no-"+o.LongName+"\t"+o.Help)
+				}
+
+				if o.ShortName != "" {
+					list = append(list, "-"+o.ShortName+"\t"+o.Help)
+				}
+			}
+		}
+	}
+
+	return list
+}
+
+// commandNames returns the command names of the groups in order.
+func (opt *Options) commandNames() []string {
+	list := []string{}
+	for _, g := range opt.GetGroups() {
+		list = append(list, g.commands...)
+	}
+
+	return list
+}
+
+// filterCandidates returns the candidates starting with prefix.
+func filterCandidates(list []string, prefix string) []string {
+	out := []string{}
+	for _, c := range list {
+		if strings.HasPrefix(c, prefix) {
+			out = append(out, c)
+		}
+	}
+
+	return out
+}
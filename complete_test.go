@@ -0,0 +1,68 @@
+package sopt_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/grimdork/sopt"
+)
+
+func completeOptions() *sopt.Options {
+	opt := sopt.New()
+	opt.SetOption("", "v", "verbose", "Show more details in output.", false, false, sopt.VarTypeBool, nil)
+	opt.SetOption("", "f", "format", "Output format.", "json", false, sopt.VarTypeString, []any{"json", "yaml"})
+	remote := opt.SetCommand("remote", "Manage remotes.", "", nil, []string{"r"})
+	rm := remote.Options.SetCommand("rm", "Remove a remote.", "", nil, nil)
+	rm.Complete = func(args []string, word string) []string {
+		return []string{"origin", "upstream"}
+	}
+	return opt
+}
+
+func TestComplete(t *testing.T) {
+	opt := completeOptions()
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{[]string{""}, "remote\tManage remotes.,r\tManage remotes."},
+		{[]string{"--f"}, "--format\tOutput format."},
+		{[]string{"--format", "y"}, "yaml"},
+		{[]string{"--format", "=", ""}, "json,yaml"},
+		{[]string{"--format=j"}, "--format=json"},
+		{[]string{"-v", "remote", "rm", "u"}, "upstream"},
+		{[]string{"r", "rm", "--verb"}, "--verbose\tShow more details in output."},
+	}
+
+	for _, tt := range tests {
+		got := strings.Join(opt.Complete(tt.args), ",")
+		if got != tt.want {
+			t.Errorf("Complete(%q): expected %q, but got %q", tt.args, tt.want, got)
+			t.Fail()
+		}
+	}
+}
+
+func TestCompletionScripts(t *testing.T) {
+	opt := completeOptions()
+	for _, shell := range []string{"bash", "zsh", "fish"} {
+		var buf bytes.Buffer
+		err := opt.WriteCompletion(&buf, shell)
+		if err != nil {
+			t.Errorf("Expected no error, but got %s", err.Error())
+			t.FailNow()
+		}
+
+		if !strings.Contains(buf.String(), "__complete") {
+			t.Errorf("Expected %s script to call __complete.", shell)
+			t.Fail()
+		}
+	}
+
+	err := opt.WriteCompletion(&bytes.Buffer{}, "tcsh")
+	if err == nil {
+		t.Errorf("Expected error, but unknown shell worked.")
+		t.Fail()
+	}
+}
@@ -0,0 +1,542 @@
+package sopt
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// configValue holds the values of one configuration file entry.
+type configValue struct {
+	file   string
+	line   int
+	values []string
+}
+
+// configValues are the entries of configuration files by option.
+type configValues map[*Option]*configValue
+
+// SetConfig enables loading option values from a configuration file during parsing. If long isn't empty,
+// an option taking the path of the file is added to the default group. When that option isn't given, the
+// first existing file from ConfigPaths(tool) is loaded, if any.
+//
+// Values from the configuration file are used for options given neither on the command line nor by
+// environment variables.
+func (opt *Options) SetConfig(tool, short, long string) error {
+	if long != "" || short != "" {
+		err := opt.SetOption("", short, long, "Configuration file.", nil, false, VarTypeString, nil)
+		if err != nil {
+			return err
+		}
+
+		o := opt.GetOption(long)
+		if o == nil {
+			o = opt.GetOption(short)
+		}
+		o.Placeholder = "FILE"
+		opt.configopt = o
+	}
+
+	opt.tool = tool
+	return nil
+}
+
+// ConfigPaths returns the search path for a tool's configuration file:
+// "config", "config.json" and "config.ini" in $XDG_CONFIG_HOME/<tool>, or $HOME/.config/<tool>.
+func ConfigPaths(tool string) []string {
+	dir := os.Getenv("XDG_CONFIG_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil
+		}
+
+		dir = filepath.Join(home, ".config")
+	}
+
+	dir = filepath.Join(dir, tool)
+	return []string{
+		filepath.Join(dir, "config"),
+		filepath.Join(dir, "config.json"),
+		filepath.Join(dir, "config.ini"),
+	}
+}
+
+// loadConfigFile reads the configuration file named by the config option or its environment variable, or
+// found in the search path.
+func (opt *Options) loadConfigFile(r *Result) (configValues, error) {
+	// The file may be named by the option's environment variable, which must be known before reading it.
+	if opt.configopt != nil {
+		err := opt.applyEnvOption(r, opt.configopt)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	path, ok := r.lookup(opt.configopt)
+	if ok {
+		return opt.readConfig(path.(string))
+	}
+
+	if opt.tool == "" {
+		return nil, nil
+	}
+
+	for _, path := range ConfigPaths(opt.tool) {
+		_, err := os.Stat(path)
+		if err == nil {
+			return opt.readConfig(path)
+		}
+	}
+
+	return nil, nil
+}
+
+// LoadConfig loads option values from a JSON or INI file. Files ending in ".json", or starting with "{",
+// are read as JSON. Anything else is read as INI, which also covers simple TOML files.
+//
+// Keys are the long names of options. Sections (INI) or nested objects (JSON) are named after option
+// groups, and only match options in that group, or after commands, and match the command's options.
+// Nested commands and their groups are written as paths in INI sections, like [remote.add]. Keys outside
+// any section match options in any group. Keys not defined at the level of their section set the options
+// of that name in the commands below it.
+// Slices are written as arrays, or as repeated keys in INI files.
+//
+// Values are applied when parsing, for options not given on the command line or by environment variables.
+// If ParseArgs is already done, they are applied right away to options without a value.
+func (opt *Options) LoadConfig(path string) error {
+	cfg, err := opt.readConfig(path)
+	if err != nil {
+		return err
+	}
+
+	if opt.config == nil {
+		opt.config = make(configValues)
+	}
+
+	for o, cv := range cfg {
+		opt.config[o] = cv
+	}
+
+	r := opt.latest()
+	if r == nil || !r.has(opt) {
+		return nil
+	}
+
+	for _, o := range r.path {
+		err = o.applyConfig(r, []configValues{cfg})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// readConfig reads the entries of a JSON or INI file.
+func (opt *Options) readConfig(path string) (configValues, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := make(configValues)
+	if strings.HasSuffix(path, ".json") || bytes.HasPrefix(bytes.TrimSpace(data), []byte("{")) {
+		err = opt.loadJSON(cfg, path, data)
+	} else {
+		err = opt.loadINI(cfg, path, data)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+// configOptions returns the options matching a key in a section. Sections name a group or a command
+// at this level, or a path of commands optionally ending in a group, separated by dots ("remote.add").
+// Keys not defined at the level of their section match the options of that name in all commands below it.
+func (opt *Options) configOptions(section, key string) []*Option {
+	level := opt
+	var group *Group
+	if section != "" {
+		for _, name := range strings.Split(section, ".") {
+			if group != nil {
+				return nil
+			}
+
+			cmd := level.commands[name]
+			if cmd != nil {
+				level = cmd.Options
+				continue
+			}
+
+			group = level.groups[name]
+			if group == nil {
+				return nil
+			}
+		}
+	}
+
+	if group != nil {
+		for _, o := range group.options {
+			if o.LongName == key {
+				return []*Option{o}
+			}
+		}
+
+		return nil
+	}
+
+	o := level.long[key]
+	if o != nil {
+		return []*Option{o}
+	}
+
+	return level.commandLongOptions(key)
+}
+
+// commandLongOptions returns the long options called key in all commands below this level.
+func (opt *Options) commandLongOptions(key string) []*Option {
+	list := []*Option{}
+	for _, g := range opt.GetGroups() {
+		for _, name := range g.commands {
+			sub := opt.commands[name].Options
+			o := sub.long[key]
+			if o != nil {
+				list = append(list, o)
+				continue
+			}
+
+			list = append(list, sub.commandLongOptions(key)...)
+		}
+	}
+
+	return list
+}
+
+// owns returns true if o is defined at this level.
+func (opt *Options) owns(o *Option) bool {
+	return o.LongName != "" && opt.long[o.LongName] == o || o.ShortName != "" && opt.short[o.ShortName] == o
+}
+
+// setConfig validates and stores the values of an entry.
+func (opt *Options) setConfig(cfg configValues, path string, line int, section, key string, values []string) error {
+	name := key
+	if section != "" {
+		name = section + "." + key
+	}
+
+	list := opt.configOptions(section, key)
+	if len(list) == 0 {
+		return &ConfigError{File: path, Line: line, Key: name, Err: ErrUnknownOption}
+	}
+
+	for _, o := range list {
+		for _, v := range values {
+			err := o.check(v)
+			if err != nil {
+				return &ConfigError{File: path, Line: line, Key: name, Err: err}
+			}
+		}
+
+		cv := cfg[o]
+		if cv != nil && cv.file == path && (o.Type == VarTypeStringSlice || o.Type == VarTypePosStringSlice) {
+			cv.values = append(cv.values, values...)
+			continue
+		}
+
+		cfg[o] = &configValue{file: path, line: line, values: values}
+	}
+
+	return nil
+}
+
+// applyConfig sets the options of this level without a value from configuration entries, taking the
+// first entry found for each option.
+func (opt *Options) applyConfig(r *Result, cfgs []configValues) error {
+	for _, cfg := range cfgs {
+		for o, cv := range cfg {
+			if r.isSet(o) || !opt.owns(o) {
+				continue
+			}
+
+			for _, v := range cv.values {
+				err := r.set(o, v, Source{Kind: SourceConfig, File: cv.file, Line: cv.line})
+				if err != nil {
+					ce := &ConfigError{File: cv.file, Line: cv.line, Key: o.LongName, Err: err}
+					err = opt.fail(r, newParseError(-1, v, o.name(), ce))
+					if err != nil {
+						return err
+					}
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// loadINI reads sections and key-value pairs. Comments start with "#" or ";". Values may be quoted,
+// and arrays are written in brackets: tags = [a, "b c"].
+func (opt *Options) loadINI(cfg configValues, path string, data []byte) error {
+	section := ""
+	line := 0
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line++
+		s := strings.TrimSpace(scanner.Text())
+		if s == "" || s[0] == '#' || s[0] == ';' {
+			continue
+		}
+
+		if s[0] == '[' {
+			if !strings.HasSuffix(s, "]") {
+				return &ConfigError{File: path, Line: line, Key: s, Err: ErrConfigSyntax}
+			}
+
+			section = strings.TrimSpace(s[1 : len(s)-1])
+			continue
+		}
+
+		a := strings.SplitN(s, "=", 2)
+		if len(a) != 2 {
+			return &ConfigError{File: path, Line: line, Key: s, Err: ErrConfigSyntax}
+		}
+
+		key := strings.TrimSpace(a[0])
+		values, err := parseINIValue(strings.TrimSpace(a[1]))
+		if err != nil {
+			return &ConfigError{File: path, Line: line, Key: key, Err: err}
+		}
+
+		err = opt.setConfig(cfg, path, line, section, key, values)
+		if err != nil {
+			return err
+		}
+	}
+
+	return scanner.Err()
+}
+
+// parseINIValue returns the elements of an array, or the value as the only element.
+func parseINIValue(s string) ([]string, error) {
+	if !strings.HasPrefix(s, "[") {
+		v, rest, err := parseINIString(s, false)
+		if err != nil {
+			return nil, err
+		}
+
+		if rest != "" && rest[0] != '#' && rest[0] != ';' {
+			return nil, ErrConfigSyntax
+		}
+
+		return []string{v}, nil
+	}
+
+	list := []string{}
+	s = strings.TrimSpace(s[1:])
+	for {
+		if strings.HasPrefix(s, "]") {
+			return list, nil
+		}
+
+		v, rest, err := parseINIString(s, true)
+		if err != nil {
+			return nil, err
+		}
+
+		list = append(list, v)
+		switch {
+		case strings.HasPrefix(rest, ","):
+			s = strings.TrimSpace(rest[1:])
+		case strings.HasPrefix(rest, "]"):
+			return list, nil
+		default:
+			return nil, ErrConfigSyntax
+		}
+	}
+}
+
+// parseINIString returns a quoted or bare value from the start of s and what follows it.
+// Bare values end at a comma, bracket or comment inside arrays, and at a comment otherwise.
+// Comments start with "#" or ";" at the start of the value or after whitespace.
+func parseINIString(s string, array bool) (string, string, error) {
+	if s == "" {
+		return "", "", nil
+	}
+
+	switch s[0] {
+	case '"':
+		end := 1
+		for end < len(s) && s[end] != '"' {
+			if s[end] == '\\' {
+				end++
+			}
+			end++
+		}
+
+		if end >= len(s) {
+			return "", "", ErrConfigSyntax
+		}
+
+		v, err := strconv.Unquote(s[:end+1])
+		if err != nil {
+			return "", "", ErrConfigSyntax
+		}
+
+		return v, strings.TrimSpace(s[end+1:]), nil
+
+	case '\'':
+		end := strings.IndexByte(s[1:], '\'')
+		if end < 0 {
+			return "", "", ErrConfigSyntax
+		}
+
+		return s[1 : end+1], strings.TrimSpace(s[end+2:]), nil
+	}
+
+	for end := 0; end < len(s); end++ {
+		switch s[end] {
+		case ',', ']':
+			if array {
+				return strings.TrimSpace(s[:end]), s[end:], nil
+			}
+
+		case '#', ';':
+			if end == 0 || s[end-1] == ' ' || s[end-1] == '\t' {
+				return strings.TrimSpace(s[:end]), s[end:], nil
+			}
+		}
+	}
+
+	return strings.TrimSpace(s), "", nil
+}
+
+// loadJSON reads an object of keys and values, where nested objects are sections.
+func (opt *Options) loadJSON(cfg configValues, path string, data []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	tok, err := dec.Token()
+	if err != nil {
+		return &ConfigError{File: path, Line: lineAt(data, dec.InputOffset()), Err: err}
+	}
+
+	if tok != json.Delim('{') {
+		return &ConfigError{File: path, Line: 1, Err: ErrConfigSyntax}
+	}
+
+	return opt.loadJSONObject(cfg, path, data, dec, "")
+}
+
+// loadJSONObject reads the entries of an object until its closing brace.
+func (opt *Options) loadJSONObject(cfg configValues, path string, data []byte, dec *json.Decoder, section string) error {
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return &ConfigError{File: path, Line: lineAt(data, dec.InputOffset()), Key: section, Err: err}
+		}
+
+		key, _ := tok.(string)
+		line := lineAt(data, dec.InputOffset())
+		tok, err = dec.Token()
+		if err != nil {
+			return &ConfigError{File: path, Line: line, Key: key, Err: err}
+		}
+
+		values := []string{}
+		switch tok {
+		case json.Delim('{'):
+			sub := key
+			if section != "" {
+				sub = section + "." + key
+			}
+
+			err = opt.loadJSONObject(cfg, path, data, dec, sub)
+			if err != nil {
+				return err
+			}
+
+			continue
+
+		case json.Delim('['):
+			for dec.More() {
+				tok, err = dec.Token()
+				if err != nil {
+					return &ConfigError{File: path, Line: line, Key: key, Err: err}
+				}
+
+				v, ok := jsonScalar(tok)
+				if !ok {
+					return &ConfigError{File: path, Line: line, Key: key, Err: ErrConfigSyntax}
+				}
+
+				values = append(values, v)
+			}
+
+			// Closing bracket.
+			_, err = dec.Token()
+			if err != nil {
+				return &ConfigError{File: path, Line: line, Key: key, Err: err}
+			}
+
+		default:
+			v, ok := jsonScalar(tok)
+			if !ok {
+				return &ConfigError{File: path, Line: line, Key: key, Err: ErrConfigSyntax}
+			}
+
+			// Null leaves the option unset.
+			if tok == nil {
+				continue
+			}
+
+			values = append(values, v)
+		}
+
+		err = opt.setConfig(cfg, path, line, section, key, values)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Closing brace.
+	_, err := dec.Token()
+	if err != nil && !errors.Is(err, io.EOF) {
+		return &ConfigError{File: path, Line: lineAt(data, dec.InputOffset()), Key: section, Err: err}
+	}
+
+	return nil
+}
+
+// jsonScalar returns a JSON string, number, boolean or null token as a string.
+func jsonScalar(tok json.Token) (string, bool) {
+	switch v := tok.(type) {
+	case string:
+		return v, true
+	case json.Number:
+		return v.String(), true
+	case bool:
+		return strconv.FormatBool(v), true
+	case nil:
+		return "", true
+	}
+
+	return "", false
+}
+
+// lineAt returns the line number of an offset in data.
+func lineAt(data []byte, offset int64) int {
+	if offset > int64(len(data)) {
+		offset = int64(len(data))
+	}
+
+	return bytes.Count(data[:offset], []byte("\n")) + 1
+}
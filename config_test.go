@@ -0,0 +1,212 @@
+package sopt_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/grimdork/sopt"
+)
+
+func configOptions() *sopt.Options {
+	opt := sopt.New()
+	opt.SetOption("", "v", "verbose", "Show more details in output.", false, false, sopt.VarTypeBool, nil)
+	opt.SetOption("Network", "p", "port", "Port number.", 3000, false, sopt.VarTypeInt, nil)
+	opt.SetOption("Network", "H", "host", "Host name.", "localhost", false, sopt.VarTypeString, nil)
+	opt.SetOption("", "t", "tag", "Tags.", nil, false, sopt.VarTypeStringSlice, nil)
+	return opt
+}
+
+func writeConfig(t *testing.T, name, data string) string {
+	path := filepath.Join(t.TempDir(), name)
+	err := os.WriteFile(path, []byte(data), 0600)
+	if err != nil {
+		t.Fatalf("Couldn't write config: %s", err.Error())
+	}
+
+	return path
+}
+
+func TestConfigINI(t *testing.T) {
+	path := writeConfig(t, "config", `# Test configuration
+verbose = yes
+tag = [a, "b c"]
+
+[Network]
+port = 9000 ; inline comment
+host = "example.com"
+`)
+	opt := configOptions()
+	opt.SetConfig("mytool", "c", "config")
+	opt.PrintHelp()
+	err := opt.ParseArgs([]string{"--config", path, "--host", "cli.example.com"})
+	if err != nil {
+		t.Errorf("Expected no error, but got %s", err.Error())
+		t.FailNow()
+	}
+
+	if !opt.GetBool("verbose") || opt.GetInt("port") != 9000 {
+		t.Errorf("Expected values from config, but got verbose=%v port=%d", opt.GetBool("verbose"), opt.GetInt("port"))
+		t.Fail()
+	}
+
+	if opt.GetString("host") != "cli.example.com" {
+		t.Errorf("Expected command line to win over config, but got %s", opt.GetString("host"))
+		t.Fail()
+	}
+
+	tags := opt.GetStringSlice("tag")
+	if len(tags) != 2 || tags[1] != "b c" {
+		t.Errorf("Expected [a b c], but got %+v", tags)
+		t.Fail()
+	}
+}
+
+func TestConfigINIBareValues(t *testing.T) {
+	path := writeConfig(t, "config", `host = hello, world
+tag = [http://x/y#frag, a;b] # comment
+[Network]
+`)
+	opt := configOptions()
+	opt.SetConfig("mytool", "c", "config")
+	err := opt.ParseArgs([]string{"--config", path})
+	if err != nil {
+		t.Errorf("Expected no error, but got %s", err.Error())
+		t.FailNow()
+	}
+
+	if opt.GetString("host") != "hello, world" {
+		t.Errorf("Expected 'hello, world', but got '%s'", opt.GetString("host"))
+		t.Fail()
+	}
+
+	tags := opt.GetStringSlice("tag")
+	if len(tags) != 2 || tags[0] != "http://x/y#frag" || tags[1] != "a;b" {
+		t.Errorf("Expected [http://x/y#frag a;b], but got %+v", tags)
+		t.Fail()
+	}
+}
+
+func TestConfigJSON(t *testing.T) {
+	path := writeConfig(t, "config.json", `{
+	"verbose": true,
+	"tag": ["x", "y"],
+	"Network": {
+		"port": 8080
+	}
+}`)
+	opt := configOptions()
+	err := opt.LoadConfig(path)
+	if err != nil {
+		t.Errorf("Expected no error, but got %s", err.Error())
+		t.FailNow()
+	}
+
+	err = opt.ParseArgs([]string{})
+	if err != nil {
+		t.Errorf("Expected no error, but got %s", err.Error())
+		t.FailNow()
+	}
+
+	if !opt.GetBool("v") || opt.GetInt("p") != 8080 || len(opt.GetStringSlice("t")) != 2 {
+		t.Errorf("Expected values from config, but got verbose=%v port=%d", opt.GetBool("v"), opt.GetInt("p"))
+		t.Fail()
+	}
+}
+
+func TestConfigErrors(t *testing.T) {
+	path := writeConfig(t, "config.ini", "verbose = yes\n\n[Network]\nport = many\n")
+	err := configOptions().LoadConfig(path)
+	var ce *sopt.ConfigError
+	if !errors.As(err, &ce) || ce.Line != 4 || ce.Key != "Network.port" {
+		t.Errorf("Expected config error on line 4, but got %v", err)
+		t.Fail()
+	} else {
+		t.Logf("Mistyped entry failed as expected: %s", err.Error())
+	}
+
+	path = writeConfig(t, "config.json", "{\n\t\"verbose\": true,\n\t\"colour\": \"red\"\n}")
+	err = configOptions().LoadConfig(path)
+	if !errors.As(err, &ce) || ce.Line != 3 || !errors.Is(err, sopt.ErrUnknownOption) {
+		t.Errorf("Expected unknown option on line 3, but got %v", err)
+		t.Fail()
+	} else {
+		t.Logf("Unknown entry failed as expected: %s", err.Error())
+	}
+}
+
+func TestConfigCommands(t *testing.T) {
+	newOptions := func() (*sopt.Options, *sopt.Command, *sopt.Command) {
+		opt := sopt.New()
+		opt.SetConfig("", "c", "config")
+		serve := opt.SetCommand("serve", "Serve.", "", func([]string) error { return nil }, nil)
+		serve.Options.SetOption("", "p", "port", "Port.", 80, false, sopt.VarTypeInt, nil)
+		remote := opt.SetCommand("remote", "Remotes.", "", nil, nil)
+		add := remote.Options.SetCommand("add", "Add.", "", func([]string) error { return nil }, nil)
+		add.Options.SetOption("Naming", "n", "name", "Name.", nil, false, sopt.VarTypeString, nil)
+		return opt, serve, add
+	}
+
+	path := writeConfig(t, "config", "port = 8080\n")
+	opt, serve, _ := newOptions()
+	err := opt.ParseArgs([]string{"-c", path, "serve"})
+	if err != nil || serve.Options.GetInt("port") != 8080 {
+		t.Errorf("Expected port 8080 for serve, but got %d and %v", serve.Options.GetInt("port"), err)
+		t.Fail()
+	}
+
+	path = writeConfig(t, "config", "[serve]\nport = 9000\n\n[remote.add.Naming]\nname = origin\n")
+	opt, serve, add := newOptions()
+	err = opt.ParseArgs([]string{"-c", path, "remote", "add"})
+	if err != nil || add.Options.GetString("name") != "origin" {
+		t.Errorf("Expected the name from [remote.add.Naming], but got %q and %v", add.Options.GetString("name"), err)
+		t.Fail()
+	}
+
+	err = opt.ParseArgs([]string{"-c", path, "serve"})
+	if err != nil || serve.Options.GetInt("port") != 9000 {
+		t.Errorf("Expected port 9000 from [serve], but got %d and %v", serve.Options.GetInt("port"), err)
+		t.Fail()
+	}
+
+	path = writeConfig(t, "config.json", `{"remote": {"add": {"Naming": {"name": "upstream"}}}}`)
+	opt, _, add = newOptions()
+	err = opt.ParseArgs([]string{"-c", path, "remote", "add"})
+	if err != nil || add.Options.GetString("name") != "upstream" {
+		t.Errorf("Expected the name from nested JSON, but got %q and %v", add.Options.GetString("name"), err)
+		t.Fail()
+	}
+
+	path = writeConfig(t, "config", "[serve]\nname = x\n")
+	opt, _, _ = newOptions()
+	err = opt.ParseArgs([]string{"-c", path, "serve"})
+	if !errors.Is(err, sopt.ErrUnknownOption) {
+		t.Errorf("Expected ErrUnknownOption for an option of another command, but got %v", err)
+		t.Fail()
+	}
+}
+
+func TestConfigEnv(t *testing.T) {
+	path := writeConfig(t, "c.ini", "port = 9000\n")
+	t.Setenv("T_CONFIG", path)
+	opt := configOptions()
+	opt.SetEnvPrefix("T_")
+	opt.SetConfig("", "c", "config")
+	err := opt.ParseArgs([]string{})
+	if err != nil {
+		t.Errorf("Expected no error, but got %s", err.Error())
+		t.FailNow()
+	}
+
+	if opt.GetInt("port") != 9000 {
+		t.Errorf("Expected port from the file named in the environment, but got %d", opt.GetInt("port"))
+		t.Fail()
+	}
+
+	src := opt.Source("config")
+	if src.Kind != sopt.SourceEnv || src.Name != "T_CONFIG" {
+		t.Errorf("Expected the config option from $T_CONFIG, but got %s", src)
+		t.Fail()
+	}
+}
@@ -0,0 +1,171 @@
+package sopt
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ConstraintKind is the type of relationship between options.
+type ConstraintKind uint8
+
+// Constraint kinds
+const (
+	// ConstraintExclusive allows at most one of the options.
+	ConstraintExclusive ConstraintKind = iota
+	// ConstraintTogether requires all or none of the options.
+	ConstraintTogether
+	// ConstraintOneRequired requires at least one of the options.
+	ConstraintOneRequired
+	// ConstraintRequires requires the other options when the first one is set.
+	ConstraintRequires
+)
+
+// constraint between options, checked after parsing.
+type constraint struct {
+	kind    ConstraintKind
+	options []*Option
+}
+
+// MutuallyExclusive allows at most one of the named options to be set. Options are named by their long
+// or short names, and may be defined by parents. Booleans set to false, as with "--no-json", and counters
+// set to zero count as not set for all constraints.
+func (opt *Options) MutuallyExclusive(names ...string) error {
+	return opt.addConstraint(ConstraintExclusive, names)
+}
+
+// RequiredTogether requires either all or none of the named options to be set.
+func (opt *Options) RequiredTogether(names ...string) error {
+	return opt.addConstraint(ConstraintTogether, names)
+}
+
+// OneRequired requires at least one of the named options to be set.
+func (opt *Options) OneRequired(names ...string) error {
+	return opt.addConstraint(ConstraintOneRequired, names)
+}
+
+// Requires requires the options named by deps to be set when the option called name is.
+func (opt *Options) Requires(name string, deps ...string) error {
+	return opt.addConstraint(ConstraintRequires, append([]string{name}, deps...))
+}
+
+// addConstraint looks up the named options and adds a constraint between them.
+func (opt *Options) addConstraint(kind ConstraintKind, names []string) error {
+	if len(names) < 2 {
+		return fmt.Errorf("%s: %w", strings.Join(names, ","), ErrConstraintOptions)
+	}
+
+	c := constraint{kind: kind}
+	for _, name := range names {
+		o := opt.GetOption(name)
+		if o == nil {
+			return fmt.Errorf("%s: %w", name, ErrUnknownOption)
+		}
+
+		c.options = append(c.options, o)
+	}
+
+	opt.constraints = append(opt.constraints, c)
+	return nil
+}
+
+// Constraints returns descriptions of the relationships between options, as shown in help text.
+func (opt *Options) Constraints() []string {
+	list := []string{}
+	for _, c := range opt.constraints {
+		names := nameList(c.options)
+		switch c.kind {
+		case ConstraintExclusive:
+			list = append(list, "Only one of "+joinNames(names, "or")+" can be used.")
+		case ConstraintTogether:
+			list = append(list, joinNames(names, "and")+" must be used together.")
+		case ConstraintOneRequired:
+			list = append(list, "One of "+joinNames(names, "or")+" is required.")
+		case ConstraintRequires:
+			list = append(list, names[0]+" requires "+joinNames(names[1:], "and")+".")
+		}
+	}
+
+	return list
+}
+
+// checkConstraints returns an error for the first constraint which isn't met.
+// In ModeCollectErrors all of them are collected instead.
+func (opt *Options) checkConstraints(r *Result) error {
+	for _, c := range opt.constraints {
+		set, missing := []*Option{}, []*Option{}
+		for _, o := range c.options {
+			if r.active(o) {
+				set = append(set, o)
+			} else {
+				missing = append(missing, o)
+			}
+		}
+
+		fail := false
+		switch c.kind {
+		case ConstraintExclusive:
+			fail = len(set) > 1
+		case ConstraintTogether:
+			fail = len(set) > 0 && len(missing) > 0
+		case ConstraintOneRequired:
+			fail = len(set) == 0
+		case ConstraintRequires:
+			fail = r.active(c.options[0]) && len(missing) > 0
+		}
+
+		if !fail {
+			continue
+		}
+
+		ce := &ConstraintError{Kind: c.kind, Set: nameList(set), Missing: nameList(missing)}
+		name := c.options[0].name()
+		if len(set) > 0 {
+			name = set[0].name()
+		}
+
+		err := opt.fail(r, newParseError(-1, "", name, ce))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// active returns true if the option got a value while parsing. Booleans set to false and counters set to
+// zero count as not given.
+func (r *Result) active(o *Option) bool {
+	if !r.isSet(o) {
+		return false
+	}
+
+	switch o.Type {
+	case VarTypeBool:
+		v, _ := value[bool](r, o)
+		return v
+	case VarTypeCount:
+		v, _ := value[int](r, o)
+		return v > 0
+	}
+
+	return true
+}
+
+// nameList returns the names of options as written on the command line.
+func nameList(options []*Option) []string {
+	list := make([]string, len(options))
+	for i, o := range options {
+		list[i] = o.name()
+	}
+
+	return list
+}
+
+// joinNames returns a list of names in a sentence, as in "a, b or c".
+func joinNames(names []string, conj string) string {
+	if len(names) < 2 {
+		return strings.Join(names, "")
+	}
+
+	return strings.Join(names[:len(names)-1], ", ") + " " + conj + " " + names[len(names)-1]
+}
@@ -0,0 +1,98 @@
+package sopt_test
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/grimdork/sopt"
+)
+
+func newConstrained(t *testing.T) *sopt.Options {
+	opt := sopt.New()
+	opt.SetOption("", "j", "json", "Print JSON.", nil, false, sopt.VarTypeBool, nil)
+	opt.SetOption("", "t", "table", "Print a table.", nil, false, sopt.VarTypeBool, nil)
+	opt.SetOption("", "", "tls-cert", "Certificate.", nil, false, sopt.VarTypeString, nil)
+	opt.SetOption("", "", "tls-key", "Key.", nil, false, sopt.VarTypeString, nil)
+	opt.SetOption("", "", "user", "User name.", nil, false, sopt.VarTypeString, nil)
+	opt.SetOption("", "", "token", "Token.", nil, false, sopt.VarTypeString, nil)
+	opt.SetOption("", "", "ca", "CA file.", nil, false, sopt.VarTypeString, nil)
+	for _, err := range []error{
+		opt.MutuallyExclusive("json", "t"),
+		opt.RequiredTogether("tls-cert", "tls-key"),
+		opt.OneRequired("user", "token"),
+		opt.Requires("ca", "tls-cert"),
+	} {
+		if err != nil {
+			t.Errorf("Expected no error, but got %s", err.Error())
+			t.FailNow()
+		}
+	}
+
+	return opt
+}
+
+func TestConstraints(t *testing.T) {
+	tests := []struct {
+		args []string
+		msg  string
+	}{
+		{[]string{"--user=x", "-j"}, ""},
+		{[]string{"--user=x", "-j", "-t"}, "--json and --table can't be used together"},
+		{[]string{"--user=x", "--json", "--no-table"}, ""},
+		{[]string{"--user=x", "--no-json", "-t"}, ""},
+		{[]string{"--user=x", "--json", "--table=false"}, ""},
+		{[]string{"--user=x", "--tls-key=k"}, "--tls-key must be used with --tls-cert"},
+		{[]string{"-j"}, "one of --user or --token is required"},
+		{[]string{"--token=x", "--ca=c"}, "--ca requires --tls-cert"},
+		{[]string{"--token=x", "--ca=c", "--tls-cert=c", "--tls-key=k"}, ""},
+	}
+
+	for _, tc := range tests {
+		err := newConstrained(t).ParseArgs(tc.args)
+		if tc.msg == "" {
+			if err != nil {
+				t.Errorf("%v: expected no error, but got %s", tc.args, err.Error())
+				t.Fail()
+			}
+
+			continue
+		}
+
+		var ce *sopt.ConstraintError
+		if !errors.Is(err, sopt.ErrConstraint) || !errors.As(err, &ce) || err.Error() != tc.msg {
+			t.Errorf("%v: expected %q, but got %v", tc.args, tc.msg, err)
+			t.Fail()
+		}
+	}
+
+	err := sopt.New().MutuallyExclusive("json", "table")
+	if !errors.Is(err, sopt.ErrUnknownOption) {
+		t.Errorf("Expected ErrUnknownOption, but got %v", err)
+		t.Fail()
+	}
+}
+
+func TestConstraintsEnvFalse(t *testing.T) {
+	t.Setenv("MYTOOL_TABLE", "false")
+	opt := newConstrained(t)
+	opt.SetEnvPrefix("MYTOOL_")
+	err := opt.ParseArgs([]string{"--user=x", "--json"})
+	if err != nil {
+		t.Errorf("Expected no error, but got %s", err.Error())
+		t.Fail()
+	}
+}
+
+func TestConstraintsHelp(t *testing.T) {
+	opt := newConstrained(t)
+	var b bytes.Buffer
+	opt.WriteHelp(&b)
+	want := "Constraints:\n  Only one of --json or --table can be used.\n  --tls-cert and --tls-key must be used together.\n" +
+		"  One of --user or --token is required.\n  --ca requires --tls-cert.\n"
+	if !strings.Contains(b.String(), want) {
+		t.Errorf("Expected constraints in help, but got:\n%s", b.String())
+		t.Fail()
+	}
+}
@@ -0,0 +1,228 @@
+package sopt
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// about returns the description, or the help text of the command owning these options.
+func (opt *Options) about() string {
+	if opt.description != "" {
+		return opt.description
+	}
+
+	if opt.parent != nil {
+		cmd := opt.parent.commands[opt.name]
+		if cmd != nil {
+			return cmd.Help
+		}
+	}
+
+	return ""
+}
+
+// pageName returns the name of the documentation page for these options, e.g. "tool-remote-add".
+func (opt *Options) pageName() string {
+	return strings.ReplaceAll(opt.usageName(), " ", "-")
+}
+
+// commandOptions returns the options of each command, in group order.
+func (opt *Options) commandOptions() []*Options {
+	list := []*Options{}
+	for _, name := range opt.commandNames() {
+		list = append(list, opt.commands[name].Options)
+	}
+
+	return list
+}
+
+// GenerateMan writes a man page for the tool and one for each command into dir, for use with go generate.
+// Pages are named after the command path, e.g. "tool-remote-add.1". The tool name is taken from the IO
+// set with SetIO, and otherwise from os.Args[0], which is the name of a temporary binary under go generate
+// or go run. Set it with SetIO(&IO{Name: "tool"}) first.
+func (opt *Options) GenerateMan(dir string) error {
+	return opt.generate(dir, ".1", (*Options).WriteMan)
+}
+
+// GenerateMarkdown writes a Markdown page for the tool and one for each command into dir, for use with
+// go generate. Pages are named after the command path, e.g. "tool-remote-add.md". As with GenerateMan,
+// set the tool name with SetIO(&IO{Name: "tool"}) first.
+func (opt *Options) GenerateMarkdown(dir string) error {
+	return opt.generate(dir, ".md", (*Options).WriteMarkdown)
+}
+
+// generate writes a page per level of the command tree.
+func (opt *Options) generate(dir, ext string, write func(*Options, io.Writer) error) error {
+	f, err := os.Create(filepath.Join(dir, opt.pageName()+ext))
+	if err != nil {
+		return err
+	}
+
+	err = write(opt, f)
+	if err != nil {
+		f.Close()
+		return err
+	}
+
+	err = f.Close()
+	if err != nil {
+		return err
+	}
+
+	for _, sub := range opt.commandOptions() {
+		err = sub.generate(dir, ext, write)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// WriteMan writes a section 1 man page in roff format, with NAME, SYNOPSIS, DESCRIPTION, OPTIONS, COMMANDS,
+// ARGUMENTS and SEE ALSO sections as applicable. The tool name is the Name of the IO set with SetIO, or
+// the base name of os.Args[0].
+func (opt *Options) WriteMan(w io.Writer) error {
+	var b strings.Builder
+	name := opt.usageName()
+	fmt.Fprintf(&b, ".TH %q 1\n", strings.ToUpper(opt.pageName()))
+	b.WriteString(".SH NAME\n")
+	b.WriteString(roffEscape(opt.pageName()))
+	if about := opt.about(); about != "" {
+		b.WriteString(` \- ` + roffEscape(about))
+	}
+	b.WriteString("\n")
+
+	b.WriteString(".SH SYNOPSIS\n")
+	fmt.Fprintf(&b, ".B %s\n%s\n", roffEscape(name), roffEscape(strings.TrimSpace(opt.synopsis())))
+
+	if opt.description != "" {
+		fmt.Fprintf(&b, ".SH DESCRIPTION\n%s\n", roffEscape(opt.description))
+	}
+
+	headed := false
+	for _, g := range opt.GetGroups() {
+		if len(g.options) == 0 {
+			continue
+		}
+
+		if !headed {
+			b.WriteString(".SH OPTIONS\n")
+			headed = true
+		}
+
+		fmt.Fprintf(&b, ".SS %s\n", roffEscape(g.title("options")))
+		for _, o := range g.options {
+			fmt.Fprintf(&b, ".TP\n.B %s\n%s\n", roffEscape(optionLabel(o)), roffEscape(o.Help+opt.optionNotes(o)))
+		}
+	}
+
+	headed = false
+	for _, g := range opt.GetGroups() {
+		if len(g.commands) == 0 {
+			continue
+		}
+
+		if !headed {
+			b.WriteString(".SH COMMANDS\n")
+			headed = true
+		}
+
+		fmt.Fprintf(&b, ".SS %s\n", roffEscape(g.title("commands")))
+		for _, n := range g.commands {
+			cmd := opt.commands[n]
+			fmt.Fprintf(&b, ".TP\n.B %s\n%s\n", roffEscape(cmd.Name), roffEscape(cmd.Help+commandNotes(cmd)))
+		}
+	}
+
+	if len(opt.positional) > 0 {
+		b.WriteString(".SH ARGUMENTS\n")
+		for _, o := range opt.positional {
+			fmt.Fprintf(&b, ".TP\n.I %s\n%s\n", roffEscape(o.Placeholder), roffEscape(o.Help+opt.optionNotes(o)))
+		}
+	}
+
+	see := []string{}
+	if opt.parent != nil {
+		see = append(see, opt.parent.pageName())
+	}
+
+	for _, sub := range opt.commandOptions() {
+		see = append(see, sub.pageName())
+	}
+
+	if len(see) > 0 {
+		b.WriteString(".SH SEE ALSO\n")
+		for i, s := range see {
+			if i > 0 {
+				b.WriteString(",\n")
+			}
+			fmt.Fprintf(&b, ".BR %s (1)", roffEscape(s))
+		}
+		b.WriteString("\n")
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// roffEscape escapes backslashes, hyphens and leading control characters.
+func roffEscape(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\e`)
+	s = strings.ReplaceAll(s, "-", `\-`)
+	lines := strings.Split(s, "\n")
+	for i, l := range lines {
+		if strings.HasPrefix(l, ".") || strings.HasPrefix(l, "'") {
+			lines[i] = `\&` + l
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// WriteMarkdown writes a Markdown reference page. Commands link to their own pages, as written by
+// GenerateMarkdown. The tool name is the Name of the IO set with SetIO, or the base name of os.Args[0].
+func (opt *Options) WriteMarkdown(w io.Writer) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n\n", opt.usageName())
+	if about := opt.about(); about != "" {
+		fmt.Fprintf(&b, "%s\n\n", about)
+	}
+
+	fmt.Fprintf(&b, "## Usage\n\n```\n%s%s\n```\n", opt.usageName(), opt.synopsis())
+	for _, g := range opt.GetGroups() {
+		if len(g.options) > 0 {
+			fmt.Fprintf(&b, "\n## %s\n\n| Option | Description |\n| --- | --- |\n", g.title("options"))
+			for _, o := range g.options {
+				fmt.Fprintf(&b, "| `%s` | %s |\n", optionLabel(o), markdownCell(o.Help+opt.optionNotes(o)))
+			}
+		}
+
+		if len(g.commands) > 0 {
+			fmt.Fprintf(&b, "\n## %s\n\n| Command | Description |\n| --- | --- |\n", g.title("commands"))
+			for _, n := range g.commands {
+				cmd := opt.commands[n]
+				fmt.Fprintf(&b, "| [`%s`](%s.md) | %s |\n", cmd.Name, cmd.Options.pageName(), markdownCell(cmd.Help+commandNotes(cmd)))
+			}
+		}
+	}
+
+	if len(opt.positional) > 0 {
+		b.WriteString("\n## Positional arguments\n\n| Argument | Description |\n| --- | --- |\n")
+		for _, o := range opt.positional {
+			fmt.Fprintf(&b, "| `%s` | %s |\n", o.Placeholder, markdownCell(o.Help+opt.optionNotes(o)))
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// markdownCell escapes pipes and line breaks in table cells.
+func markdownCell(s string) string {
+	s = strings.ReplaceAll(s, "|", `\|`)
+	return strings.ReplaceAll(s, "\n", "<br>")
+}
@@ -0,0 +1,102 @@
+package sopt_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/grimdork/sopt"
+)
+
+func docsOptions() *sopt.Options {
+	opt := sopt.New()
+	opt.SetDescription("Manage things.")
+	opt.SetOption("", "v", "verbose", "Show more details in output.", false, false, sopt.VarTypeBool, nil)
+	opt.SetOption("Network", "p", "port", "Port number.", 3000, true, sopt.VarTypeInt, nil)
+	remote := opt.SetCommand("remote", "Manage remotes.", "", nil, []string{"r"})
+	add := remote.Options.SetCommand("add", "Add a remote.", "", nil, nil)
+	add.Options.SetPositional("URL", "Remote URL.", nil, false, sopt.VarTypeString)
+	return opt
+}
+
+func TestWriteMan(t *testing.T) {
+	var buf bytes.Buffer
+	err := docsOptions().WriteMan(&buf)
+	if err != nil {
+		t.Errorf("Expected no error, but got %s", err.Error())
+		t.FailNow()
+	}
+
+	man := buf.String()
+	t.Log(man)
+	for _, s := range []string{".SH NAME", ".SH SYNOPSIS", ".SH OPTIONS", ".SS Network options", `\-p, \-\-port`, "(required)", ".SH COMMANDS", "(aliases: r)"} {
+		if !strings.Contains(man, s) {
+			t.Errorf("Expected man page to contain %q.", s)
+			t.Fail()
+		}
+	}
+}
+
+func TestGenerateMarkdown(t *testing.T) {
+	dir := t.TempDir()
+	err := docsOptions().GenerateMarkdown(dir)
+	if err != nil {
+		t.Errorf("Expected no error, but got %s", err.Error())
+		t.FailNow()
+	}
+
+	name := filepath.Base(os.Args[0])
+	for _, page := range []string{name, name + "-remote", name + "-remote-add"} {
+		data, err := os.ReadFile(filepath.Join(dir, page+".md"))
+		if err != nil {
+			t.Errorf("Expected page %s, but got %s", page, err.Error())
+			t.Fail()
+			continue
+		}
+
+		t.Log(string(data))
+	}
+
+	data, _ := os.ReadFile(filepath.Join(dir, name+"-remote.md"))
+	if !strings.Contains(string(data), "[`add`]("+name+"-remote-add.md)") {
+		t.Errorf("Expected link to sub-command page.")
+		t.Fail()
+	}
+}
+
+func TestGenerateManName(t *testing.T) {
+	dir := t.TempDir()
+	opt := docsOptions()
+	opt.SetIO(&sopt.IO{Name: "tool"})
+	err := opt.GenerateMan(dir)
+	if err != nil {
+		t.Errorf("Expected no error, but got %s", err.Error())
+		t.FailNow()
+	}
+
+	for _, page := range []string{"tool", "tool-remote", "tool-remote-add"} {
+		_, err := os.Stat(filepath.Join(dir, page+".1"))
+		if err != nil {
+			t.Errorf("Expected page %s, but got %s", page, err.Error())
+			t.Fail()
+		}
+	}
+}
+
+func TestSecretDefault(t *testing.T) {
+	opt := docsOptions()
+	opt.SetOption("", "t", "token", "API token.", "s3cr3t", false, sopt.VarTypeString, nil)
+	opt.GetOption("token").Secret = true
+	var help, man, md bytes.Buffer
+	opt.WriteHelp(&help)
+	opt.WriteMan(&man)
+	opt.WriteMarkdown(&md)
+	for _, s := range []string{help.String(), man.String(), md.String()} {
+		if strings.Contains(s, "s3cr3t") || !strings.Contains(s, "API token.") {
+			t.Errorf("Expected the token without its default, but got:\n%s", s)
+			t.Fail()
+		}
+	}
+}
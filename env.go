@@ -0,0 +1,108 @@
+package sopt
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// SetEnvPrefix sets the prefix for environment variable names derived from long option names and
+// positional placeholders. With the prefix "MYTOOL_", the option "--log-level" can be set by
+// MYTOOL_LOG_LEVEL. Commands use the prefix of their parents unless they set their own.
+func (opt *Options) SetEnvPrefix(prefix string) {
+	opt.envprefix = prefix
+}
+
+// SetEnv binds an environment variable to an option or positional argument, overriding any name derived
+// from the prefix.
+func (opt *Options) SetEnv(name, env string) error {
+	o := opt.GetOption(name)
+	if o == nil {
+		o = opt.posmap[name]
+	}
+
+	if o == nil {
+		return fmt.Errorf("%s: %w", name, ErrUnknownOption)
+	}
+
+	o.Env = env
+	return nil
+}
+
+// getEnvPrefix returns the prefix set here or in the nearest parent.
+func (opt *Options) getEnvPrefix() string {
+	for p := opt; p != nil; p = p.parent {
+		if p.envprefix != "" {
+			return p.envprefix
+		}
+	}
+
+	return ""
+}
+
+// envName returns the environment variable name for an option, or an empty string if it has none.
+func (opt *Options) envName(o *Option) string {
+	if o.Env != "" {
+		return o.Env
+	}
+
+	// The default help option only makes sense on the command line.
+	prefix := opt.getEnvPrefix()
+	if prefix == "" || opt.isDefaultHelp(o) {
+		return ""
+	}
+
+	name := o.LongName
+	if name == "" {
+		name = o.Placeholder
+	}
+
+	if name == "" {
+		return ""
+	}
+
+	return prefix + strings.ToUpper(strings.ReplaceAll(name, "-", "_"))
+}
+
+// applyEnv sets options and positional arguments not given on the command line from their environment
+// variables, if set.
+func (opt *Options) applyEnv(r *Result) error {
+	list := []*Option{}
+	for _, g := range opt.GetGroups() {
+		list = append(list, g.options...)
+	}
+	list = append(list, opt.positional...)
+
+	for _, o := range list {
+		err := opt.applyEnvOption(r, o)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// applyEnvOption sets an option not given on the command line from its environment variable, if set.
+func (opt *Options) applyEnvOption(r *Result, o *Option) error {
+	if r.isSet(o) {
+		return nil
+	}
+
+	name := opt.envName(o)
+	if name == "" {
+		return nil
+	}
+
+	s, ok := os.LookupEnv(name)
+	if !ok {
+		return nil
+	}
+
+	err := r.set(o, s, Source{Kind: SourceEnv, Name: name})
+	if err != nil {
+		return opt.fail(r, newParseError(-1, s, "$"+name, fmt.Errorf("$%s: %w", name, err)))
+	}
+
+	return nil
+}
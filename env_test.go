@@ -0,0 +1,102 @@
+package sopt_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/grimdork/sopt"
+)
+
+func TestEnv(t *testing.T) {
+	t.Setenv("MYTOOL_PORT", "9000")
+	t.Setenv("MYTOOL_LOG_LEVEL", "debug")
+	t.Setenv("SECRET_TOKEN", "hunter2")
+	opt := sopt.New()
+	opt.SetEnvPrefix("MYTOOL_")
+	opt.SetOption("", "p", "port", "Port number.", 3000, false, sopt.VarTypeInt, nil)
+	opt.SetOption("", "l", "log-level", "Log level.", "info", false, sopt.VarTypeString, nil)
+	opt.SetOption("", "t", "token", "API token.", nil, true, sopt.VarTypeString, nil)
+	err := opt.SetEnv("token", "SECRET_TOKEN")
+	if err != nil {
+		t.Errorf("Expected no error, but got %s", err.Error())
+		t.FailNow()
+	}
+
+	opt.PrintHelp()
+	err = opt.ParseArgs([]string{"--log-level", "warn"})
+	if err != nil {
+		t.Errorf("Expected no error, but got %s", err.Error())
+		t.FailNow()
+	}
+
+	if opt.GetInt("port") != 9000 {
+		t.Errorf("Expected port from environment, but got %d", opt.GetInt("port"))
+		t.Fail()
+	}
+
+	if opt.GetString("log-level") != "warn" {
+		t.Errorf("Expected command line to win over environment, but got %s", opt.GetString("log-level"))
+		t.Fail()
+	}
+
+	if opt.GetString("token") != "hunter2" {
+		t.Errorf("Expected required token from environment, but got %s", opt.GetString("token"))
+		t.Fail()
+	}
+}
+
+func TestEnvBadValue(t *testing.T) {
+	t.Setenv("MYTOOL_PORT", "many")
+	opt := sopt.New()
+	opt.SetEnvPrefix("MYTOOL_")
+	opt.SetOption("", "p", "port", "Port number.", 3000, false, sopt.VarTypeInt, nil)
+	err := opt.ParseArgs([]string{})
+	if err == nil {
+		t.Errorf("Expected error, but bad environment value worked.")
+		t.Fail()
+	} else {
+		t.Logf("Bad environment value failed as expected: %s", err.Error())
+	}
+}
+
+func TestEnvCollectErrors(t *testing.T) {
+	t.Setenv("MYTOOL_PORT", "many")
+	opt := sopt.New()
+	opt.SetEnvPrefix("MYTOOL_")
+	opt.SetParseMode(sopt.ModeCollectErrors)
+	opt.SetOption("", "p", "port", "Port number.", 3000, false, sopt.VarTypeInt, nil)
+	opt.SetOption("", "t", "token", "API token.", nil, true, sopt.VarTypeString, nil)
+	err := opt.ParseArgs([]string{"--bogus"})
+	var list sopt.ParseErrors
+	if !errors.As(err, &list) || len(list) != 3 {
+		t.Errorf("Expected three errors, but got %v", err)
+		t.FailNow()
+	}
+
+	if !errors.Is(list[0], sopt.ErrUnknownOption) || !errors.Is(list[1], sopt.ErrInvalidValue) ||
+		list[1].Option != "$MYTOOL_PORT" || !errors.Is(list[2], sopt.ErrMissingRequired) {
+		t.Errorf("Expected unknown option, bad environment value and missing option, but got:\n%s", err.Error())
+		t.Fail()
+	}
+}
+
+func TestEnvDefaultHelp(t *testing.T) {
+	t.Setenv("MYTOOL_HELP", "true")
+	opt := sopt.New()
+	opt.SetDefaultHelp()
+	opt.SetEnvPrefix("MYTOOL_")
+	opt.SetOption("", "p", "port", "Port number.", 3000, false, sopt.VarTypeInt, nil)
+	var buf strings.Builder
+	opt.WriteHelp(&buf)
+	if strings.Contains(buf.String(), "$MYTOOL_HELP") || !strings.Contains(buf.String(), "$MYTOOL_PORT") {
+		t.Errorf("Expected no environment variable for help, but got:\n%s", buf.String())
+		t.Fail()
+	}
+
+	err := opt.ParseArgs([]string{})
+	if err != nil || opt.GetBool("help") {
+		t.Errorf("Expected help to be unset, but got %v and %v", err, opt.GetBool("help"))
+		t.Fail()
+	}
+}
@@ -1,6 +1,10 @@
 package sopt
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
 
 var (
 	// ErrMissingRequired is returned when a required option is missing.
@@ -13,7 +17,11 @@ var (
 	ErrLongShort = errors.New("short option must be one character")
 	// ErrUnknownOption is returned when an undefined option is encountered.
 	ErrUnknownOption = errors.New("unknown option")
-	// ErrEmptyLong is returned when a long option is empty.
+	// ErrUnknownCommand is returned when an argument where a command is expected isn't one.
+	ErrUnknownCommand = errors.New("unknown command")
+	// ErrEmptyLong was returned when a long option is empty.
+	//
+	// Deprecated: a double dash on its own ends option parsing, so this is no longer returned.
 	ErrEmptyLong = errors.New("long option without a string")
 	// ErrShortLong is returned when a long option is shorter than two characters.
 	ErrShortLong = errors.New("long option must be at least two characters")
@@ -21,4 +29,299 @@ var (
 	ErrUnknownType = errors.New("unknown option type")
 	// ErrNoPlaceholder is returned when a positional argument is missing a placeholder.
 	ErrNoPlaceholder = errors.New("no placeholder")
+	// ErrInvalidChoice is returned when a value isn't one of an option's choices.
+	ErrInvalidChoice = errors.New("invalid choice")
+	// ErrInvalidValue is returned when a value can't be converted to the option's type.
+	ErrInvalidValue = errors.New("invalid value")
+	// ErrWrongType is returned when a value or default doesn't have the Go type of its option.
+	ErrWrongType = errors.New("wrong value type")
+	// ErrBindTarget is returned when Bind is given anything but a pointer to a struct.
+	ErrBindTarget = errors.New("bind target must be a pointer to a struct")
+	// ErrUnknownShell is returned when a completion script is requested for an unsupported shell.
+	ErrUnknownShell = errors.New("unknown shell")
+	// ErrConfigSyntax is returned when a configuration file can't be parsed.
+	ErrConfigSyntax = errors.New("invalid configuration syntax")
+	// ErrMissingPositional is returned when a required positional argument is missing.
+	ErrMissingPositional = errors.New("missing required positional argument")
+	// ErrTooFewArguments is returned when a positional slice gets fewer values than its minimum.
+	ErrTooFewArguments = errors.New("too few arguments")
+	// ErrTooManyArguments is returned when a positional slice gets more values than its maximum.
+	ErrTooManyArguments = errors.New("too many arguments")
+	// ErrPositionalOrder is returned when a required positional argument is defined after an optional
+	// one, or any positional argument after a slice.
+	ErrPositionalOrder = errors.New("positional argument out of order")
+	// ErrArity is returned when the arity set for a positional argument is invalid.
+	ErrArity = errors.New("invalid arity")
+	// ErrConstraint is returned when a relationship between options isn't met. See ConstraintError.
+	ErrConstraint = errors.New("option constraint not met")
+	// ErrResponseSyntax is returned when a response file has an unterminated quote.
+	ErrResponseSyntax = errors.New("unterminated quote")
+	// ErrResponseCycle is returned when a response file includes itself.
+	ErrResponseCycle = errors.New("response file includes itself")
+	// ErrResponseDepth is returned when response files include each other too deeply.
+	ErrResponseDepth = errors.New("response files nested too deeply")
+	// ErrHelpRequested is returned by Execute after printing help.
+	ErrHelpRequested = errors.New("help requested")
+	// ErrConstraintOptions is returned when a constraint is defined for fewer than two options.
+	ErrConstraintOptions = errors.New("constraint needs at least two options")
+	// ErrEchoOn is returned when prompting for a secret on a terminal whose echo can't be turned off.
+	ErrEchoOn = errors.New("can't turn off terminal echo")
 )
+
+// ChoiceError is returned when a value isn't one of an option's choices. It matches ErrInvalidChoice.
+type ChoiceError struct {
+	// Option name as written on the command line, or the placeholder of a positional argument.
+	Option string
+	// Value which was rejected.
+	Value any
+	// Choices allowed for the option.
+	Choices []any
+}
+
+// Error returns the option, value and allowed choices as a string.
+func (e *ChoiceError) Error() string {
+	return fmt.Sprintf("%s: %s %q (choices: %s)", e.Option, ErrInvalidChoice, fmt.Sprint(e.Value), joinChoices(e.Choices))
+}
+
+// Unwrap returns ErrInvalidChoice.
+func (e *ChoiceError) Unwrap() error {
+	return ErrInvalidChoice
+}
+
+// joinChoices returns the choices as a comma-separated string.
+func joinChoices(choices []any) string {
+	list := make([]string, len(choices))
+	for i, c := range choices {
+		list[i] = fmt.Sprint(c)
+	}
+
+	return strings.Join(list, ", ")
+}
+
+// ParseError is returned by ParseArgs for problems with the arguments. It matches its sentinel error,
+// such as ErrUnknownOption or ErrMissingArgument, with errors.Is, and unwraps to the underlying cause.
+type ParseError struct {
+	// Index of the argument in the parsed slice, or -1 if the error isn't about one argument.
+	Index int
+	// Arg is the argument as given.
+	Arg string
+	// Option as written on the command line, the placeholder of a positional argument, or a command.
+	Option string
+	// Err is the sentinel error.
+	Err error
+	// Cause is the underlying error, such as a *ValueError or *ChoiceError, if any.
+	Cause error
+	// Suggestions of similar options or commands for unknown ones.
+	Suggestions []string
+}
+
+// newParseError returns a ParseError. If err isn't a sentinel error, it's kept as the cause.
+func newParseError(index int, arg, option string, err error) *ParseError {
+	pe := &ParseError{Index: index, Arg: arg, Option: option, Err: err}
+	switch {
+	case errors.Is(err, ErrInvalidChoice) && err != ErrInvalidChoice:
+		pe.Err, pe.Cause = ErrInvalidChoice, err
+	case errors.Is(err, ErrConstraint) && err != ErrConstraint:
+		pe.Err, pe.Cause = ErrConstraint, err
+	case errors.Is(err, ErrUnknownType) && err != ErrUnknownType:
+		pe.Err, pe.Cause = ErrUnknownType, err
+	case !isSentinel(err):
+		pe.Err, pe.Cause = ErrInvalidValue, err
+	}
+
+	return pe
+}
+
+// isSentinel returns true for the error values of this package.
+func isSentinel(err error) bool {
+	switch err {
+	case ErrMissingRequired, ErrMissingArgument, ErrUnknownOption, ErrUnknownCommand, ErrInvalidValue,
+		ErrInvalidChoice, ErrUnknownType, ErrMissingPositional, ErrTooFewArguments, ErrTooManyArguments:
+		return true
+	}
+
+	return false
+}
+
+// Error returns the option and problem, followed by suggestions on separate lines.
+func (e *ParseError) Error() string {
+	var b strings.Builder
+	if e.Cause != nil {
+		b.WriteString(e.Cause.Error())
+	} else {
+		fmt.Fprintf(&b, "%s: %s", e.Option, e.Err)
+	}
+
+	switch len(e.Suggestions) {
+	case 0:
+	case 1:
+		fmt.Fprintf(&b, "\n\nDid you mean %s?", e.Suggestions[0])
+	default:
+		b.WriteString("\n\nDid you mean one of these?")
+		for _, s := range e.Suggestions {
+			b.WriteString("\n\t" + s)
+		}
+	}
+
+	return b.String()
+}
+
+// Is returns true for the sentinel error.
+func (e *ParseError) Is(target error) bool {
+	return target == e.Err
+}
+
+// Unwrap returns the underlying cause, if any.
+func (e *ParseError) Unwrap() error {
+	return e.Cause
+}
+
+// ConstraintError is returned when a relationship between options isn't met. It matches ErrConstraint.
+type ConstraintError struct {
+	// Kind of constraint.
+	Kind ConstraintKind
+	// Set are the options of the constraint which were set.
+	Set []string
+	// Missing are the options of the constraint which weren't set.
+	Missing []string
+}
+
+// Error describes the violated constraint.
+func (e *ConstraintError) Error() string {
+	switch e.Kind {
+	case ConstraintExclusive:
+		return joinNames(e.Set, "and") + " can't be used together"
+	case ConstraintTogether:
+		return joinNames(e.Set, "and") + " must be used with " + joinNames(e.Missing, "and")
+	case ConstraintOneRequired:
+		return "one of " + joinNames(e.Missing, "or") + " is required"
+	case ConstraintRequires:
+		return e.Set[0] + " requires " + joinNames(e.Missing, "and")
+	}
+
+	return ErrConstraint.Error()
+}
+
+// Unwrap returns ErrConstraint.
+func (e *ConstraintError) Unwrap() error {
+	return ErrConstraint
+}
+
+// ParseErrors is returned by ParseArgs in ModeCollectErrors, listing every problem found.
+// It matches any of its errors with errors.Is and errors.As.
+type ParseErrors []*ParseError
+
+// Error returns the messages of all errors, one per line.
+func (e ParseErrors) Error() string {
+	list := make([]string, len(e))
+	for i, pe := range e {
+		list[i] = pe.Error()
+	}
+
+	return strings.Join(list, "\n")
+}
+
+// Is returns true if any of the errors matches target.
+func (e ParseErrors) Is(target error) bool {
+	for _, pe := range e {
+		if errors.Is(pe, target) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// As finds the first of the errors matching target.
+func (e ParseErrors) As(target any) bool {
+	for _, pe := range e {
+		if errors.As(pe, target) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Unwrap returns the errors, for errors.Is and errors.As from Go 1.20 on.
+func (e ParseErrors) Unwrap() []error {
+	list := make([]error, len(e))
+	for i, pe := range e {
+		list[i] = pe
+	}
+
+	return list
+}
+
+// ValueError is returned when a value can't be converted to the option's type. It matches ErrInvalidValue.
+type ValueError struct {
+	// Option name as written on the command line, or the placeholder of a positional argument.
+	Option string
+	// Value which was rejected.
+	Value string
+	// Format expected by the option.
+	Format string
+	// Err is the underlying error.
+	Err error
+}
+
+// Error returns the option, value and expected format as a string.
+func (e *ValueError) Error() string {
+	return fmt.Sprintf("%s: %s %q, expected %s", e.Option, ErrInvalidValue, e.Value, e.Format)
+}
+
+// Is returns true for ErrInvalidValue.
+func (e *ValueError) Is(target error) bool {
+	return target == ErrInvalidValue
+}
+
+// Unwrap returns the underlying error.
+func (e *ValueError) Unwrap() error {
+	return e.Err
+}
+
+// ConfigError is returned for unknown or mistyped entries in a configuration file.
+type ConfigError struct {
+	// File the entry was read from.
+	File string
+	// Line of the entry.
+	Line int
+	// Key of the entry, including any section.
+	Key string
+	// Err is the underlying error.
+	Err error
+}
+
+// Error returns the location, key and cause as a string.
+func (e *ConfigError) Error() string {
+	return fmt.Sprintf("%s:%d: %s: %s", e.File, e.Line, e.Key, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *ConfigError) Unwrap() error {
+	return e.Err
+}
+
+// ResponseFileError is returned when a response file can't be read or expanded.
+type ResponseFileError struct {
+	// File with the problem, or referencing the file with the problem.
+	File string
+	// Line of the problem, or 0 for a file given on the command line.
+	Line int
+	// Err is the underlying error.
+	Err error
+}
+
+// Error returns the location and cause as a string.
+func (e *ResponseFileError) Error() string {
+	if e.Line == 0 {
+		return fmt.Sprintf("@%s: %s", e.File, e.Err)
+	}
+
+	return fmt.Sprintf("%s:%d: %s", e.File, e.Line, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *ResponseFileError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,209 @@
+package sopt
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+// HelpFormatter renders the help text for a set of options.
+type HelpFormatter interface {
+	FormatHelp(w io.Writer, opt *Options) error
+}
+
+// HelpSection is a part of the help text printed by DefaultFormatter.
+type HelpSection uint8
+
+// Help sections
+const (
+	// SectionUsage is the usage line.
+	SectionUsage HelpSection = iota
+	// SectionDescription is the description set with SetDescription.
+	SectionDescription
+	// SectionGroups are the options and commands of each group, in the order the groups were added.
+	SectionGroups
+	// SectionPositional are the positional arguments.
+	SectionPositional
+	// SectionExamples are the examples added with AddExample.
+	SectionExamples
+	// SectionEpilog is the text set with SetEpilog.
+	SectionEpilog
+	// SectionConstraints are the relationships between options, such as MutuallyExclusive.
+	SectionConstraints
+)
+
+// DefaultSections is the order of sections used when DefaultFormatter.Sections is nil.
+var DefaultSections = []HelpSection{
+	SectionUsage,
+	SectionDescription,
+	SectionGroups,
+	SectionPositional,
+	SectionConstraints,
+	SectionExamples,
+	SectionEpilog,
+}
+
+// DefaultFormatter prints help text in columns, with groups in the order they were added.
+type DefaultFormatter struct {
+	// Width of the terminal to wrap text at. Zero uses $COLUMNS, or 80 if unset. Negative disables wrapping.
+	Width int
+	// Sections to print, in order. Nil uses DefaultSections.
+	Sections []HelpSection
+}
+
+// FormatHelp writes the help text to w.
+func (f *DefaultFormatter) FormatHelp(w io.Writer, opt *Options) error {
+	width := f.width()
+	sections := f.Sections
+	if sections == nil {
+		sections = DefaultSections
+	}
+
+	tw := &tabwriter.Writer{}
+	tw.Init(w, 8, 8, 1, '\t', 0)
+	for _, s := range sections {
+		switch s {
+		case SectionUsage:
+			fmt.Fprintf(tw, "Usage:\n  %s\n\n", opt.Usage())
+
+		case SectionDescription:
+			if opt.description != "" {
+				writeWrapped(tw, "", opt.description, width)
+				tw.Write([]byte("\n"))
+			}
+
+		case SectionGroups:
+			for _, g := range opt.GetGroups() {
+				if len(g.options) > 0 {
+					rows := [][2]string{}
+					for _, o := range g.options {
+						rows = append(rows, [2]string{optionLabel(o), o.Help + opt.optionNotes(o)})
+					}
+					writeTable(tw, g.title("options"), rows, width)
+				}
+
+				if len(g.commands) > 0 {
+					rows := [][2]string{}
+					for _, name := range g.commands {
+						cmd := opt.commands[name]
+						rows = append(rows, [2]string{cmd.Name, cmd.Help + commandNotes(cmd)})
+					}
+					writeTable(tw, g.title("commands"), rows, width)
+				}
+			}
+
+		case SectionPositional:
+			if len(opt.positional) > 0 {
+				rows := [][2]string{}
+				for _, o := range opt.positional {
+					rows = append(rows, [2]string{o.Placeholder, o.Help + opt.optionNotes(o)})
+				}
+				writeTable(tw, "Positional arguments", rows, width)
+			}
+
+		case SectionConstraints:
+			if len(opt.constraints) > 0 {
+				tw.Write([]byte("Constraints:\n"))
+				for _, c := range opt.Constraints() {
+					writeWrapped(tw, "  ", c, width)
+				}
+				tw.Write([]byte("\n"))
+			}
+
+		case SectionExamples:
+			if len(opt.examples) > 0 {
+				tw.Write([]byte("Examples:\n"))
+				for _, ex := range opt.examples {
+					fmt.Fprintf(tw, "  %s %s\n", opt.usageName(), ex.Command)
+					if ex.Help != "" {
+						writeWrapped(tw, "      ", ex.Help, width)
+					}
+				}
+				tw.Write([]byte("\n"))
+			}
+
+		case SectionEpilog:
+			if opt.epilog != "" {
+				writeWrapped(tw, "", opt.epilog, width)
+				tw.Write([]byte("\n"))
+			}
+		}
+	}
+
+	return tw.Flush()
+}
+
+// width returns the width to wrap at, or 0 to not wrap.
+func (f *DefaultFormatter) width() int {
+	if f.Width < 0 {
+		return 0
+	}
+
+	if f.Width > 0 {
+		return f.Width
+	}
+
+	n, err := strconv.Atoi(os.Getenv("COLUMNS"))
+	if err == nil && n > 0 {
+		return n
+	}
+
+	return 80
+}
+
+// writeTable writes a heading and rows of names and wrapped help text, aligned in columns.
+func writeTable(w io.Writer, title string, rows [][2]string, width int) {
+	fmt.Fprintf(w, "%s:\n", title)
+	// The tab writer pads the name column to multiples of 8, after an initial tab.
+	col := 0
+	for _, r := range rows {
+		if len(r[0])+1 > col {
+			col = len(r[0]) + 1
+		}
+	}
+	col = 8 + (col+7)/8*8
+
+	for _, r := range rows {
+		lines := wrap(r[1], width-col)
+		fmt.Fprintf(w, "\t%s\t%s\n", r[0], lines[0])
+		for _, l := range lines[1:] {
+			fmt.Fprintf(w, "\t\t%s\n", l)
+		}
+	}
+	w.Write([]byte("\n"))
+}
+
+// writeWrapped writes text wrapped to width, with each line indented.
+func writeWrapped(w io.Writer, indent, text string, width int) {
+	for _, para := range strings.Split(text, "\n") {
+		for _, l := range wrap(para, width-len(indent)) {
+			fmt.Fprintf(w, "%s%s\n", indent, l)
+		}
+	}
+}
+
+// wrap splits s into lines of at most width characters, breaking at spaces. Words longer than the width
+// get lines of their own. Widths under 20 characters disable wrapping.
+func wrap(s string, width int) []string {
+	words := strings.Fields(s)
+	if width < 20 || len(words) == 0 {
+		return []string{s}
+	}
+
+	lines := []string{}
+	line := words[0]
+	for _, word := range words[1:] {
+		if len(line)+1+len(word) > width {
+			lines = append(lines, line)
+			line = word
+			continue
+		}
+
+		line += " " + word
+	}
+
+	return append(lines, line)
+}
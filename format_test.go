@@ -0,0 +1,114 @@
+package sopt_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/grimdork/sopt"
+)
+
+func helpOptions() *sopt.Options {
+	opt := sopt.New()
+	opt.SetDescription("Manage things.")
+	opt.SetEpilog("Report bugs to nobody.")
+	opt.AddExample("-v remote add origin", "Add a remote verbosely.")
+	opt.SetOption("", "v", "verbose", "Show more details in output.", false, false, sopt.VarTypeBool, nil)
+	for _, g := range []string{"Network", "Storage", "Logging", "Cache", "Output"} {
+		opt.SetOption(g, "", strings.ToLower(g), g+" settings.", nil, false, sopt.VarTypeString, nil)
+	}
+	opt.SetOption("Output", "", "long", strings.Repeat("Long help text. ", 10), nil, false, sopt.VarTypeString, nil)
+	return opt
+}
+
+func TestHelpOrder(t *testing.T) {
+	opt := helpOptions()
+	var first string
+	for i := 0; i < 10; i++ {
+		var buf bytes.Buffer
+		opt.SetOutput(&buf, nil)
+		opt.PrintHelp()
+		if i == 0 {
+			first = buf.String()
+			t.Log(first)
+			continue
+		}
+
+		if buf.String() != first {
+			t.Errorf("Expected identical help text on every run.")
+			t.FailNow()
+		}
+	}
+
+	last := -1
+	for _, s := range []string{"Usage:", "Manage things.", "Main options:", "Network options:", "Storage options:", "Logging options:", "Cache options:", "Output options:", "Examples:", "Report bugs"} {
+		i := strings.Index(first, s)
+		if i <= last {
+			t.Errorf("Expected %q after the previous section.", s)
+			t.Fail()
+		}
+		last = i
+	}
+}
+
+func TestHelpWrap(t *testing.T) {
+	opt := helpOptions()
+	var buf bytes.Buffer
+	opt.SetHelpFormatter(&sopt.DefaultFormatter{Width: 60, Sections: []sopt.HelpSection{sopt.SectionGroups, sopt.SectionUsage}})
+	opt.WriteHelp(&buf)
+	text := buf.String()
+	t.Log(text)
+	if strings.Index(text, "Usage:") < strings.Index(text, "Main options:") {
+		t.Errorf("Expected custom section order.")
+		t.Fail()
+	}
+
+	for _, l := range strings.Split(text, "\n") {
+		if expandTabs(l) > 60 {
+			t.Errorf("Expected lines of at most 60 characters, but got %q", l)
+			t.Fail()
+		}
+	}
+}
+
+// expandTabs returns the width of a line with tab stops every 8 columns.
+func expandTabs(s string) int {
+	n := 0
+	for _, c := range s {
+		if c == '\t' {
+			n = (n/8 + 1) * 8
+		} else {
+			n++
+		}
+	}
+
+	return n
+}
+
+type plainFormatter struct{}
+
+func (plainFormatter) FormatHelp(w io.Writer, opt *sopt.Options) error {
+	_, err := io.WriteString(w, opt.Usage()+"\n")
+	return err
+}
+
+func TestHelpFormatter(t *testing.T) {
+	opt := helpOptions()
+	cmd := opt.SetCommand("moo", "Have you mooed today?", "", moocmd, nil)
+	var out, errout bytes.Buffer
+	opt.SetHelpFormatter(plainFormatter{})
+	opt.SetOutput(&out, &errout)
+	cmd.Options.PrintHelp()
+	if !strings.HasSuffix(out.String(), " moo\n") {
+		t.Errorf("Expected inherited formatter and writer, but got %q", out.String())
+		t.Fail()
+	}
+
+	opt.PrintError(errors.New("moo"))
+	if !strings.HasPrefix(errout.String(), "Error: moo") {
+		t.Errorf("Expected error on error writer, but got %q", errout.String())
+		t.Fail()
+	}
+}
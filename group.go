@@ -36,3 +36,8 @@ func (g *Group) Sort() {
 func (g *Group) GetOptions() []*Option {
 	return g.options
 }
+
+// GetCommands returns the names of the commands in the group.
+func (g *Group) GetCommands() []string {
+	return g.commands
+}
@@ -2,117 +2,306 @@ package sopt
 
 import (
 	"fmt"
+	"io"
 	"os"
-	"path/filepath"
-	"text/tabwriter"
+	"strings"
 )
 
 // SetDefaultHelp sets the default help text.
 func (opt *Options) SetDefaultHelp() {
 	opt.SetOption("", "h", "help", "Print this help message.", nil, false, VarTypeBool, nil)
+	opt.long["help"].NoNegate = true
 	opt.hashelp = true
 }
 
+// isDefaultHelp returns true if o is the help option set by SetDefaultHelp here or in a parent.
+func (opt *Options) isDefaultHelp(o *Option) bool {
+	for p := opt; p != nil; p = p.parent {
+		if p.hashelp && p.long["help"] == o {
+			return true
+		}
+	}
+
+	return false
+}
+
+// SetDescription sets the description of the tool or command, shown in help text and generated
+// documentation. Commands without a description use their help text in documentation.
+func (opt *Options) SetDescription(description string) {
+	opt.description = description
+}
+
+// Description returns the description set with SetDescription.
+func (opt *Options) Description() string {
+	return opt.description
+}
+
+// SetEpilog sets text shown at the end of the help text.
+func (opt *Options) SetEpilog(epilog string) {
+	opt.epilog = epilog
+}
+
+// Epilog returns the text set with SetEpilog.
+func (opt *Options) Epilog() string {
+	return opt.epilog
+}
+
+// Example of a command line shown in help text.
+type Example struct {
+	// Command line, without the program name.
+	Command string
+	// Help text explaining the example.
+	Help string
+}
+
+// AddExample adds an example command line to the help text. The program name and command path are
+// prepended to the command line.
+func (opt *Options) AddExample(cmdline, help string) {
+	opt.examples = append(opt.examples, Example{Command: cmdline, Help: help})
+}
+
+// Examples returns the examples added with AddExample.
+func (opt *Options) Examples() []Example {
+	return opt.examples
+}
+
+// SetHelpFormatter sets the formatter used by PrintHelp. Commands use the formatter of their parents
+// unless they set their own. A nil formatter resets to the default.
+func (opt *Options) SetHelpFormatter(f HelpFormatter) {
+	opt.formatter = f
+}
+
+// SetOutput sets the writers for help text, and for help printed because of an error. Commands use the
+// writers of their parents unless they set their own. Nil writers reset to os.Stdout and os.Stderr.
+func (opt *Options) SetOutput(out, errout io.Writer) {
+	opt.out = out
+	opt.errout = errout
+}
+
+// getFormatter returns the formatter set here or in the nearest parent.
+func (opt *Options) getFormatter() HelpFormatter {
+	for p := opt; p != nil; p = p.parent {
+		if p.formatter != nil {
+			return p.formatter
+		}
+	}
+
+	return &DefaultFormatter{}
+}
+
+// getOutput returns the help writer set here or in the nearest parent.
+func (opt *Options) getOutput() io.Writer {
+	for p := opt; p != nil; p = p.parent {
+		if p.out != nil {
+			return p.out
+		}
+
+		if p.io != nil && p.io.Stdout != nil {
+			return p.io.Stdout
+		}
+	}
+
+	return os.Stdout
+}
+
+// getErrOutput returns the error writer set here or in the nearest parent.
+func (opt *Options) getErrOutput() io.Writer {
+	for p := opt; p != nil; p = p.parent {
+		if p.errout != nil {
+			return p.errout
+		}
+
+		if p.io != nil && p.io.Stderr != nil {
+			return p.io.Stderr
+		}
+	}
+
+	return os.Stderr
+}
+
 // PrintHelp builds and prints the help text based on available options.
 func (opt *Options) PrintHelp() {
-	w := &tabwriter.Writer{}
-	w.Init(os.Stdout, 8, 8, 1, '\t', 0)
-	w.Write([]byte("Usage:\n  "))
-	name := filepath.Base(os.Args[0])
-	w.Write([]byte(name))
+	opt.WriteHelp(opt.getOutput())
+}
+
+// PrintError prints the error followed by the help text to the error writer.
+func (opt *Options) PrintError(err error) {
+	w := opt.getErrOutput()
+	fmt.Fprintf(w, "Error: %s\n\n", err)
+	opt.WriteHelp(w)
+}
+
+// WriteHelp writes the help text to w with the help formatter.
+func (opt *Options) WriteHelp(w io.Writer) error {
+	return opt.getFormatter().FormatHelp(w, opt)
+}
+
+// Usage returns the usage line: the program name, command path, and a summary of what it takes.
+func (opt *Options) Usage() string {
+	return opt.usageName() + opt.synopsis()
+}
 
+// GetPositionals returns a slice of positional arguments.
+func (opt *Options) GetPositionals() []*Option {
+	return opt.positional
+}
+
+// Notes returns the annotations shown after an option's help text, such as its default and choices.
+func (opt *Options) Notes(o *Option) string {
+	return opt.optionNotes(o)
+}
+
+// synopsis returns the usage line following the program name.
+func (opt *Options) synopsis() string {
+	var b strings.Builder
 	count := 0
 	for _, g := range opt.groups {
 		count += len(g.options)
 	}
 
 	if count > 0 {
-		w.Write([]byte(" [OPTIONS]"))
+		b.WriteString(" [OPTIONS]")
 	}
 
 	if len(opt.short)+len(opt.long) > 1 {
-		w.Write([]byte("..."))
+		b.WriteString("...")
 	}
 
 	if len(opt.commands) > 0 {
-		w.Write([]byte(" [COMMAND]"))
+		b.WriteString(" [COMMAND]")
 	}
 
-	if len(opt.positional) > 0 {
-		for _, o := range opt.positional {
-			fmt.Fprintf(w, " [%s]", o.Placeholder)
-			if o.Type == VarTypePosStringSlice {
-				fmt.Fprintf(w, "...")
-			}
+	for _, o := range opt.positional {
+		name := o.Placeholder
+		if o.Type == VarTypePosStringSlice {
+			name += "..."
 		}
-	}
-	w.Write([]byte("\n\n"))
 
-	for _, g := range opt.groups {
-		if len(g.options) > 0 {
-			if g.Name == "default" {
-				w.Write([]byte("Main options:\n"))
-			} else {
-				fmt.Fprintf(w, "%s options:\n", g.Name)
-			}
-
-			for _, o := range g.options {
-				if o.ShortName != "" && o.LongName != "" {
-					fmt.Fprintf(w, "\t-%s, --%s\t%s", o.ShortName, o.LongName, o.Help)
-				}
-
-				if o.ShortName != "" && o.LongName == "" {
-					fmt.Fprintf(w, "\t-%s\t%s", o.ShortName, o.Help)
-				}
-
-				if o.LongName != "" && o.ShortName == "" {
-					fmt.Fprintf(w, "\t--%s\t%s", o.LongName, o.Help)
-				}
-
-				if o.Required {
-					w.Write([]byte(" (required)"))
-				}
-
-				if o.Default != nil {
-					fmt.Fprintf(w, " (default: %v)", o.Default)
-				}
-
-				w.Write([]byte("\n"))
-			} // for range g.options
-			w.Write([]byte("\n"))
+		if o.Required {
+			fmt.Fprintf(&b, " <%s>", name)
+		} else {
+			fmt.Fprintf(&b, " [%s]", name)
 		}
+	}
 
-		if len(g.commands) > 0 {
-			if g.Name == "default" {
-				w.Write([]byte("Main commands:\n"))
-			} else {
-				fmt.Fprintf(w, "%s commands:\n", g.Name)
-			}
-
-			for _, cmd := range g.commands {
-				fmt.Fprintf(w, "\t%s\t%s", cmd, opt.commands[cmd].Help)
-				if len(opt.commands[cmd].Aliases) > 0 {
-					fmt.Fprintf(w, " (aliases: ")
-					for i, alias := range opt.commands[cmd].Aliases {
-						if i == 0 {
-							fmt.Fprintf(w, "%s", alias)
-						} else {
-							fmt.Fprintf(w, ",%s", alias)
-						}
-					}
-					fmt.Fprintf(w, ")\n")
-				}
-			}
-			w.Write([]byte("\n"))
-		}
+	return b.String()
+}
+
+// title returns the heading of a group's options or commands.
+func (g *Group) title(kind string) string {
+	if g.Name == "default" {
+		return "Main " + kind
 	}
 
-	if len(opt.positional) > 0 {
-		w.Write([]byte("Positional arguments:\n"))
-		for _, o := range opt.positional {
-			fmt.Fprintf(w, "\t%s\t%s", o.Placeholder, o.Help)
-		}
-		w.Write([]byte("\n"))
+	return g.Name + " " + kind
+}
+
+// optionLabel returns the short and long names of an option as written on the command line,
+// followed by its placeholder, if any.
+func optionLabel(o *Option) string {
+	label := o.Placeholder
+	long := "--" + o.LongName
+	if o.negatable() {
+		long = "--[no-]" + o.LongName
+	}
+
+	switch {
+	case o.ShortName != "" && o.LongName != "":
+		label = "-" + o.ShortName + ", " + long
+	case o.ShortName != "":
+		label = "-" + o.ShortName
+	case o.LongName != "":
+		label = long
+	default:
+		return label
+	}
+
+	if o.Placeholder != "" {
+		label += " " + o.Placeholder
+	}
+
+	return label
+}
+
+// optionNotes returns the annotations following an option's help text.
+func (opt *Options) optionNotes(o *Option) string {
+	var b strings.Builder
+	env := opt.envName(o)
+	if env != "" {
+		fmt.Fprintf(&b, " [$%s]", env)
+	}
+
+	if o.Type == VarTypeStringSlice || o.Type == VarTypeCount {
+		b.WriteString(" (repeatable)")
+	}
+
+	if o.Required {
+		b.WriteString(" (required)")
 	}
-	w.Flush()
+
+	b.WriteString(arityNote(o.MinArgs, o.MaxArgs))
+
+	if o.Default != nil && !o.Secret {
+		fmt.Fprintf(&b, " (default: %s)", formatValue(o.Default))
+	}
+
+	if len(o.Choices) > 0 {
+		fmt.Fprintf(&b, " (choices: %s)", joinChoices(o.Choices))
+	}
+
+	return b.String()
+}
+
+// arityNote returns the number of values a positional slice takes, if limited.
+func arityNote(min, max int) string {
+	switch {
+	case min == 1 && max == 1:
+		return " (1 value)"
+	case min > 0 && min == max:
+		return fmt.Sprintf(" (%d values)", min)
+	case min > 0 && max > 0:
+		return fmt.Sprintf(" (%d-%d values)", min, max)
+	case min > 1:
+		return fmt.Sprintf(" (at least %d values)", min)
+	case max == 1:
+		return " (at most 1 value)"
+	case max > 0:
+		return fmt.Sprintf(" (at most %d values)", max)
+	}
+
+	return ""
+}
+
+// commandNotes returns the annotations following a command's help text.
+func commandNotes(cmd *Command) string {
+	if len(cmd.Aliases) == 0 {
+		return ""
+	}
+
+	return " (aliases: " + strings.Join(cmd.Aliases, ",") + ")"
+}
+
+// usageName returns the program name followed by the path of commands leading to these options.
+func (opt *Options) usageName() string {
+	name := opt.getIO().name()
+	path := opt.commandPath()
+	if path == "" {
+		return name
+	}
+
+	return name + " " + path
+}
+
+// commandPath returns the names of the commands leading to these options.
+func (opt *Options) commandPath() string {
+	if opt.parent == nil {
+		return ""
+	}
+
+	path := opt.parent.commandPath()
+	if path == "" {
+		return opt.name
+	}
+
+	return path + " " + opt.name
 }
@@ -0,0 +1,69 @@
+package sopt
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// IO holds what Execute and Parse take from the process, so tools can be run in tests. Unset fields
+// fall back to os.Args, os.Stdin, os.Stdout, os.Stderr, os.Exit and the base name of os.Args[0].
+type IO struct {
+	// Args are the command line arguments without the program name. Nil uses os.Args.
+	Args []string
+	// Stdin is read by prompts.
+	Stdin io.Reader
+	// Stdout receives help text and completion candidates.
+	Stdout io.Writer
+	// Stderr receives help text printed because of an error.
+	Stderr io.Writer
+	// Exit is called by Parse after printing help.
+	Exit func(code int)
+	// Name of the program in help text, completion scripts and generated documentation.
+	Name string
+}
+
+// SetIO sets the arguments, streams, exit function and program name used instead of the process's.
+// Commands use the IO of their parents unless they set their own. Writers set with SetOutput take
+// precedence over the IO's writers at the same level.
+func (opt *Options) SetIO(sys *IO) {
+	opt.io = sys
+}
+
+// getIO returns the IO set here or in the nearest parent, or an empty one.
+func (opt *Options) getIO() *IO {
+	for p := opt; p != nil; p = p.parent {
+		if p.io != nil {
+			return p.io
+		}
+	}
+
+	return &IO{}
+}
+
+// args returns the command line arguments.
+func (sys *IO) args() []string {
+	if sys.Args == nil {
+		return os.Args[1:]
+	}
+
+	return sys.Args
+}
+
+// exit ends the program with code.
+func (sys *IO) exit(code int) {
+	if sys.Exit == nil {
+		os.Exit(code)
+	}
+
+	sys.Exit(code)
+}
+
+// name returns the name of the program.
+func (sys *IO) name() string {
+	if sys.Name == "" {
+		return filepath.Base(os.Args[0])
+	}
+
+	return sys.Name
+}
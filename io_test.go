@@ -0,0 +1,74 @@
+package sopt_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/grimdork/sopt"
+)
+
+func TestExecute(t *testing.T) {
+	var out, errout bytes.Buffer
+	opt := sopt.New()
+	opt.SetDefaultHelp()
+	opt.SetOption("", "n", "name", "Name.", nil, false, sopt.VarTypeString, nil)
+	opt.SetIO(&sopt.IO{Args: []string{"-n", "x"}, Stdout: &out, Stderr: &errout, Name: "tool"})
+	err := opt.Execute(context.Background(), true)
+	if err != nil || opt.GetString("name") != "x" {
+		t.Errorf("Expected the name to be parsed, but got %v", err)
+		t.Fail()
+	}
+
+	opt.SetIO(&sopt.IO{Args: []string{}, Stdout: &out, Name: "tool"})
+	err = opt.Execute(context.Background(), true)
+	if !errors.Is(err, sopt.ErrHelpRequested) || !strings.HasPrefix(out.String(), "Usage:\n  tool [OPTIONS]") {
+		t.Errorf("Expected help for empty arguments, but got %v and:\n%s", err, out.String())
+		t.Fail()
+	}
+
+	code := -1
+	out.Reset()
+	opt.SetIO(&sopt.IO{Args: []string{"--help"}, Stdout: &out, Exit: func(c int) { code = c }, Name: "tool"})
+	err = opt.Parse(false)
+	if !errors.Is(err, sopt.ErrHelpRequested) || code != 0 || out.Len() == 0 {
+		t.Errorf("Expected help and exit code 0, but got %v and %d", err, code)
+		t.Fail()
+	}
+
+	if errout.Len() != 0 {
+		t.Errorf("Expected nothing on stderr, but got %s", errout.String())
+		t.Fail()
+	}
+}
+
+func TestIOCommand(t *testing.T) {
+	var out bytes.Buffer
+	opt := sopt.New()
+	opt.SetIO(&sopt.IO{Args: []string{"add", "-h"}, Stdout: &out, Name: "tool"})
+	add := opt.SetCommand("add", "Add things.", "", func([]string) error { return nil }, nil)
+	add.Options.SetDefaultHelp()
+	err := opt.Execute(context.Background(), false)
+	if !errors.Is(err, sopt.ErrHelpRequested) || !strings.HasPrefix(out.String(), "Usage:\n  tool add [OPTIONS]") {
+		t.Errorf("Expected help for the command, but got %v and:\n%s", err, out.String())
+		t.Fail()
+	}
+}
+
+func TestExecuteHelpRequired(t *testing.T) {
+	var out bytes.Buffer
+	opt := sopt.New()
+	opt.SetDefaultHelp()
+	opt.SetOption("", "n", "name", "Name.", nil, true, sopt.VarTypeString, nil)
+	opt.SetOption("", "a", "all", "All.", false, false, sopt.VarTypeBool, nil)
+	opt.SetOption("", "o", "one", "One.", false, false, sopt.VarTypeBool, nil)
+	opt.OneRequired("all", "one")
+	opt.SetIO(&sopt.IO{Args: []string{"-h"}, Stdout: &out, Name: "tool"})
+	err := opt.Execute(context.Background(), false)
+	if !errors.Is(err, sopt.ErrHelpRequested) || !strings.HasPrefix(out.String(), "Usage:\n  tool") {
+		t.Errorf("Expected help despite the missing options, but got %v and:\n%s", err, out.String())
+		t.Fail()
+	}
+}
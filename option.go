@@ -1,6 +1,11 @@
 package sopt
 
-import "fmt"
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
 
 // Option definition.
 type Option struct {
@@ -14,16 +19,36 @@ type Option struct {
 	Help string
 
 	// Value of the option.
+	//
+	// Deprecated: parsing no longer changes definitions, so this isn't set. Use the getters or a Result.
 	Value any
+	// Var parses and holds the value of VarTypeValue options.
+	Var Value
 	// Default value if unspecified.
 	Default any
 	// Choices allowed for the option.
 	Choices []any
+	// Env is the name of an environment variable supplying the value if not given on the command line.
+	Env string
+	// Complete returns completion candidates for the option's value, used instead of Choices.
+	Complete CompleteFunc
+	// Separator splits each value of a string slice option into several elements when set, e.g. ",".
+	Separator string
+	// MinArgs and MaxArgs limit the number of values of a positional slice. See SetArity.
+	MinArgs int
+	MaxArgs int
 
 	// Type of value.
 	Type uint8
 	// Required is true if this must be defined. A default would satisfy this.
 	Required bool
+	// NoNegate disables the "--no-" form of long boolean options.
+	NoNegate bool
+	// Secret hides the value when prompting for it and in reports, and the default in help text and
+	// generated documentation. Echo is turned off with the stty command when prompting on the terminal
+	// of the process, and the prompt fails with ErrEchoOn where that doesn't work, as on Windows. Input
+	// from the Stdin of an IO set with SetIO is read as is.
+	Secret bool
 }
 
 // Variable types
@@ -36,13 +61,25 @@ const (
 	VarTypeFloat
 	// 	VarTypeString option.
 	VarTypeString
-	// VarTypeStringSlice option.
+	// VarTypeStringSlice option. It may be repeated, and each occurrence adds to the slice.
 	VarTypeStringSlice
 	// VarTypePosStringSlice option.
 	VarTypePosStringSlice
+	// VarTypeValue option, parsed by a custom Value.
+	VarTypeValue
+	// VarTypeDuration option, parsed by time.ParseDuration.
+	VarTypeDuration
+	// VarTypeSize option, in bytes with an optional unit. See Size.
+	VarTypeSize
+	// VarTypeTime option, in RFC 3339 format or as a date.
+	VarTypeTime
+	// VarTypeURL option, an absolute URL.
+	VarTypeURL
+	// VarTypeCount option, an int counting how many times the option is given.
+	VarTypeCount
 )
 
-// SetOption sets an option.
+// SetOption sets an option. The default value must be nil or have the Go type of the option type.
 func (opt *Options) SetOption(group, short, long, help string, defaultvalue any, required bool, t uint8, choices []any) error {
 	if len(short) > 1 {
 		return fmt.Errorf("-%s: %w", short, ErrLongShort)
@@ -52,11 +89,6 @@ func (opt *Options) SetOption(group, short, long, help string, defaultvalue any,
 		return fmt.Errorf("--%s: %w", long, ErrShortLong)
 	}
 
-	g := opt.GetGroup(group)
-	if g == nil {
-		g = opt.AddGroup(group)
-	}
-
 	o := &Option{
 		ShortName: short,
 		LongName:  long,
@@ -67,6 +99,16 @@ func (opt *Options) SetOption(group, short, long, help string, defaultvalue any,
 		Required:  required,
 	}
 
+	err := checkType(o.name(), t, defaultvalue)
+	if err != nil {
+		return err
+	}
+
+	g := opt.GetGroup(group)
+	if g == nil {
+		g = opt.AddGroup(group)
+	}
+
 	g.options = append(g.options, o)
 	if short != "" {
 		opt.short[short] = o
@@ -78,3 +120,191 @@ func (opt *Options) SetOption(group, short, long, help string, defaultvalue any,
 
 	return nil
 }
+
+// name returns the option as it is written on the command line, or the placeholder for positional arguments.
+func (o *Option) name() string {
+	if o.LongName != "" {
+		return "--" + o.LongName
+	}
+
+	if o.ShortName != "" {
+		return "-" + o.ShortName
+	}
+
+	return o.Placeholder
+}
+
+// count returns the number of values of a positional slice.
+func (r *Result) count(o *Option) int {
+	v, _ := r.values[o].([]string)
+	return len(v)
+}
+
+// set converts s to the option's type and stores it, checking it against any choices.
+// Slices get s appended to their value.
+func (r *Result) set(o *Option, s string, src Source) error {
+	switch o.Type {
+	case VarTypeValue:
+		return r.setVar(o, s, src)
+
+	case VarTypePosStringSlice, VarTypeStringSlice:
+		parts, err := o.parseSlice(s)
+		if err != nil {
+			return err
+		}
+
+		// The first explicit value replaces the default rather than appending to it.
+		list, _ := r.values[o].([]string)
+		r.put(o, append(list, parts...), src)
+
+	default:
+		v, err := o.parseValue(s)
+		if err != nil {
+			return err
+		}
+
+		r.put(o, v, src)
+	}
+
+	return nil
+}
+
+// increment adds one to a counter.
+func (r *Result) increment(o *Option, src Source) {
+	n, _ := r.values[o].(int)
+	r.put(o, n+1, src)
+}
+
+// negatable returns true if the option accepts the "--no-" form.
+func (o *Option) negatable() bool {
+	return o.Type == VarTypeBool && o.LongName != "" && !o.NoNegate
+}
+
+// takesArg returns true if the option needs an argument when given without "=value".
+func (o *Option) takesArg() bool {
+	return o.Type != VarTypeBool && o.Type != VarTypeCount && !o.boolValue()
+}
+
+// formatError returns a ValueError naming the option, the value and the expected format.
+func (o *Option) formatError(s string, err error) error {
+	return &ValueError{Option: o.name(), Value: s, Format: typeFormat(o.Type), Err: err}
+}
+
+// check returns an error if s can't be stored by set.
+func (o *Option) check(s string) error {
+	// Custom values can't be tried without changing them.
+	if o.Type == VarTypeValue {
+		return o.checkChoice(s)
+	}
+
+	if o.Type == VarTypePosStringSlice || o.Type == VarTypeStringSlice {
+		_, err := o.parseSlice(s)
+		return err
+	}
+
+	_, err := o.parseValue(s)
+	return err
+}
+
+// parseValue converts s to the option's type, checking it against any choices.
+// Slices return s as one string element.
+func (o *Option) parseValue(s string) (any, error) {
+	var v any
+	switch o.Type {
+	case VarTypeBool:
+		_, v = isTruthy(s)
+
+	case VarTypeInt, VarTypeCount:
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			return nil, o.formatError(s, err)
+		}
+
+		v = n
+
+	case VarTypeFloat:
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return nil, o.formatError(s, err)
+		}
+
+		v = f
+
+	case VarTypeDuration:
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return nil, o.formatError(s, err)
+		}
+
+		v = d
+
+	case VarTypeSize:
+		n, err := ParseSize(s)
+		if err != nil {
+			return nil, o.formatError(s, err)
+		}
+
+		v = n
+
+	case VarTypeTime:
+		t, err := parseTime(s)
+		if err != nil {
+			return nil, o.formatError(s, err)
+		}
+
+		v = t
+
+	case VarTypeURL:
+		u, err := parseURL(s)
+		if err != nil {
+			return nil, o.formatError(s, err)
+		}
+
+		v = u
+
+	case VarTypeString, VarTypeStringSlice, VarTypePosStringSlice:
+		v = s
+
+	default:
+		return nil, fmt.Errorf("%s: %w", o.name(), ErrUnknownType)
+	}
+
+	err := o.checkChoice(v)
+	if err != nil {
+		return nil, err
+	}
+
+	return v, nil
+}
+
+// parseSlice splits s by the separator, if any, and checks each element against any choices.
+func (o *Option) parseSlice(s string) ([]string, error) {
+	parts := []string{s}
+	if o.Separator != "" && o.Type == VarTypeStringSlice {
+		parts = strings.Split(s, o.Separator)
+	}
+
+	for _, p := range parts {
+		_, err := o.parseValue(p)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return parts, nil
+}
+
+// checkChoice returns a ChoiceError if the option has choices and v isn't one of them.
+func (o *Option) checkChoice(v any) error {
+	if len(o.Choices) == 0 {
+		return nil
+	}
+
+	for _, c := range o.Choices {
+		if c == v || fmt.Sprint(c) == fmt.Sprint(v) {
+			return nil
+		}
+	}
+
+	return &ChoiceError{Option: o.name(), Value: v, Choices: o.Choices}
+}
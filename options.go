@@ -1,5 +1,11 @@
 package sopt
 
+import (
+	"io"
+	"strings"
+	"sync"
+)
+
 // Options base definition.
 type Options struct {
 	short      map[string]*Option
@@ -10,10 +16,98 @@ type Options struct {
 	commands   map[string]*Command
 	// Order of groups.
 	order []string
-	// Remainder contains args not parsed as options, commands or positional args.
+	// Remainder contains args not parsed as options, commands or positional args by the most recent
+	// ParseArgs. See Result.Remainder for ParseResult.
 	Remainder []string
 	// hashelp is true if default help is defined.
 	hashelp bool
+	// parent is the Options of the enclosing command, if any.
+	parent *Options
+	// name of the command owning these options.
+	name string
+	// envprefix is prepended to environment variable names derived from option names.
+	envprefix string
+	// tool name used to find the configuration file.
+	tool string
+	// configopt is the option naming the configuration file.
+	configopt *Option
+	// config holds values loaded from configuration files.
+	config configValues
+	// binds are the struct fields registered with Bind.
+	binds []binding
+	// completion is true if the hidden completion command is enabled.
+	completion bool
+	// description of the tool or command.
+	description string
+	// epilog shown at the end of the help text.
+	epilog string
+	// examples shown in the help text.
+	examples []Example
+	// formatter renders the help text.
+	formatter HelpFormatter
+	// out receives help text.
+	out io.Writer
+	// errout receives help text printed because of an error.
+	errout io.Writer
+	// mode of parsing.
+	mode ParseMode
+	// hasmode is true if the mode was set for these options.
+	hasmode bool
+	// constraints between options.
+	constraints []constraint
+	// io replaces the process's arguments, streams and exit function.
+	io *IO
+	// last is the result of the most recent ParseArgs, used by the getters.
+	last *Result
+	// mu guards last.
+	mu sync.Mutex
+}
+
+// ParseMode selects how ParseArgs treats options and positional arguments.
+type ParseMode uint8
+
+// Parse modes
+const (
+	// ModeGNU allows options and positional arguments in any order. This is the default.
+	ModeGNU ParseMode = 0
+	// ModePOSIX ends option parsing at the first positional argument. Everything after it is positional.
+	ModePOSIX ParseMode = 1 << (iota - 1)
+	// ModeForwardUnknown adds unknown options to Remainder instead of returning an error.
+	// It can be combined with the other modes, as in ModePOSIX|ModeForwardUnknown.
+	ModeForwardUnknown
+	// ModeCollectErrors keeps parsing after errors, and returns all of them in argument order as
+	// ParseErrors, followed by missing required options. It can be combined with the other modes.
+	ModeCollectErrors
+	// ModePrompt asks for the values of required options and positional arguments missing after parsing,
+	// when reading from a terminal or the Stdin of the IO set with SetIO. Options marked Secret aren't
+	// echoed. It can be combined with the other modes.
+	ModePrompt
+	// ModeResponseFiles replaces "@file" arguments with the arguments in the file, which are split like a
+	// shell does, with quotes, backslash escapes and "#" comments. Files may include other files, relative
+	// to themselves. "@@arg" passes "@arg" on, and nothing after "--" is expanded. The indexes in
+	// errors and sources are those of the expanded arguments. It can be combined with the other modes.
+	ModeResponseFiles
+	// ModeStrictCommands returns ErrUnknownCommand for a positional argument where only commands are
+	// expected, with the closest commands as suggestions. Without it the argument goes to Remainder.
+	// It can be combined with the other modes.
+	ModeStrictCommands
+)
+
+// SetParseMode sets the parsing mode. Commands use the mode of their parents unless they set their own.
+func (opt *Options) SetParseMode(mode ParseMode) {
+	opt.mode = mode
+	opt.hasmode = true
+}
+
+// getParseMode returns the mode set here or in the nearest parent.
+func (opt *Options) getParseMode() ParseMode {
+	for p := opt; p != nil; p = p.parent {
+		if p.hasmode {
+			return p.mode
+		}
+	}
+
+	return ModeGNU
 }
 
 // New options instance.
@@ -72,23 +166,75 @@ func (opt *Options) RemoveGroup(name string) {
 	}
 }
 
+// Parent returns the Options of the enclosing command, or nil for the top level.
+func (opt *Options) Parent() *Options {
+	return opt.parent
+}
+
 // GetOption returns a pointer to an option.
+// Options not defined at this level are looked up in the parent commands.
 func (opt *Options) GetOption(name string) *Option {
-	var o *Option
-	var ok bool
 	if len(name) > 1 {
-		o, ok = opt.long[name]
-	} else {
-		o, ok = opt.short[name]
+		return opt.lookupLong(name)
+	}
+
+	return opt.lookupShort(name)
+}
+
+// lookupLong finds a long option here or in any parent.
+func (opt *Options) lookupLong(name string) *Option {
+	for p := opt; p != nil; p = p.parent {
+		o, ok := p.long[name]
+		if ok {
+			return o
+		}
+	}
+
+	return nil
+}
+
+// lookupShort finds a short option here or in any parent.
+func (opt *Options) lookupShort(name string) *Option {
+	for p := opt; p != nil; p = p.parent {
+		o, ok := p.short[name]
+		if ok {
+			return o
+		}
+	}
+
+	return nil
+}
+
+// lookupNegated finds the boolean option negated by a long name starting with "no-".
+func (opt *Options) lookupNegated(name string) *Option {
+	if !strings.HasPrefix(name, "no-") {
+		return nil
 	}
 
-	if !ok {
+	o := opt.lookupLong(name[3:])
+	if o == nil || !o.negatable() {
 		return nil
 	}
 
 	return o
 }
 
+// knownShorts returns true if every character in s is a short option here or in a parent.
+func (opt *Options) knownShorts(s string) bool {
+	for _, c := range s {
+		if opt.lookupShort(string(c)) == nil {
+			return false
+		}
+	}
+
+	return true
+}
+
+// empty returns true if nothing has been defined on these options.
+func (opt *Options) empty() bool {
+	return len(opt.short)+len(opt.long)+len(opt.positional)+len(opt.commands) == 0
+}
+
 // GetBool returns a bool option's value.
 func (opt *Options) GetBool(name string) bool {
 	o := opt.GetOption(name)
@@ -96,15 +242,41 @@ func (opt *Options) GetBool(name string) bool {
 		return false
 	}
 
-	if o.Value == nil {
-		if o.Default != nil {
-			return o.Default.(bool)
-		}
+	v, _ := value[bool](opt.latest(), o)
+	return v
+}
 
-		return false
+// Tristate of a boolean option.
+type Tristate int8
+
+// Tristate values
+const (
+	// TristateUnset means the option wasn't given on the command line, by environment or by configuration.
+	TristateUnset Tristate = iota
+	// TristateFalse means the option was set to false.
+	TristateFalse
+	// TristateTrue means the option was set to true.
+	TristateTrue
+)
+
+// GetTristate returns whether a bool option was set to true or false, or not set at all.
+// The default value is ignored.
+func (opt *Options) GetTristate(name string) Tristate {
+	o := opt.GetOption(name)
+	if o == nil {
+		return TristateUnset
+	}
+
+	v, _ := opt.latest().lookup(o)
+	b, ok := v.(bool)
+	switch {
+	case !ok:
+		return TristateUnset
+	case b:
+		return TristateTrue
 	}
 
-	return o.Value.(bool)
+	return TristateFalse
 }
 
 // GetString returns a string option's value.
@@ -114,15 +286,8 @@ func (opt *Options) GetString(name string) string {
 		return ""
 	}
 
-	if o.Value == nil {
-		if o.Default != nil {
-			return o.Default.(string)
-		}
-
-		return ""
-	}
-
-	return o.Value.(string)
+	v, _ := value[string](opt.latest(), o)
+	return v
 }
 
 // GetStringSlice returns a string slice option's value.
@@ -132,15 +297,12 @@ func (opt *Options) GetStringSlice(name string) []string {
 		return []string{}
 	}
 
-	if o.Value == nil {
-		if o.Default != nil {
-			return o.Default.([]string)
-		}
-
+	v, _ := value[[]string](opt.latest(), o)
+	if v == nil {
 		return []string{}
 	}
 
-	return o.Value.([]string)
+	return v
 }
 
 // GetInt returns an int option's value.
@@ -150,15 +312,8 @@ func (opt *Options) GetInt(name string) int {
 		return 0
 	}
 
-	if o.Value == nil {
-		if o.Default != nil {
-			return o.Default.(int)
-		}
-
-		return 0
-	}
-
-	return o.Value.(int)
+	v, _ := value[int](opt.latest(), o)
+	return v
 }
 
 // GetFloat returns a float option's value.
@@ -168,13 +323,6 @@ func (opt *Options) GetFloat(name string) float64 {
 		return 0.0
 	}
 
-	if o.Value == nil {
-		if o.Default != nil {
-			return o.Default.(float64)
-		}
-
-		return 0.0
-	}
-
-	return o.Value.(float64)
+	v, _ := value[float64](opt.latest(), o)
+	return v
 }
@@ -1,7 +1,9 @@
 package sopt_test
 
 import (
+	"errors"
 	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/grimdork/sopt"
@@ -299,3 +301,358 @@ func TestSPositionalSlice(t *testing.T) {
 		t.Logf("File paths are as expected: %+v", files)
 	}
 }
+
+func TestChoices(t *testing.T) {
+	opt := sopt.New()
+	err := opt.SetOption("", "f", "format", "Output format.", "json", false, sopt.VarTypeString, []any{"json", "yaml"})
+	if err != nil {
+		t.Errorf("Expected no error, but got %s", err.Error())
+		t.FailNow()
+	}
+
+	opt.PrintHelp()
+	err = opt.ParseArgs([]string{"--format=yaml"})
+	if err != nil {
+		t.Errorf("Expected no error, but got %s", err.Error())
+		t.FailNow()
+	}
+
+	err = opt.ParseArgs([]string{"-f", "xml"})
+	if !errors.Is(err, sopt.ErrInvalidChoice) {
+		t.Errorf("Expected invalid choice, but got %v", err)
+		t.FailNow()
+	}
+
+	var ce *sopt.ChoiceError
+	if !errors.As(err, &ce) || ce.Option != "--format" || ce.Value != "xml" || len(ce.Choices) != 2 {
+		t.Errorf("Expected choice error details, but got %+v", ce)
+		t.Fail()
+	} else {
+		t.Logf("Invalid choice failed as expected: %s", err.Error())
+	}
+}
+
+func TestPositionalChoices(t *testing.T) {
+	opt := sopt.New()
+	opt.SetPositional("LEVEL", "Levels.", nil, false, sopt.VarTypePosStringSlice)
+	opt.GetPositional("LEVEL").Choices = []any{"low", "high"}
+	err := opt.ParseArgs([]string{"low", "medium"})
+	if !errors.Is(err, sopt.ErrInvalidChoice) {
+		t.Errorf("Expected invalid choice, but got %v", err)
+		t.Fail()
+	}
+}
+
+func TestStringSlice(t *testing.T) {
+	opt := sopt.New()
+	err := opt.SetOption("", "I", "include", "Include path.", []string{"/usr/include"}, false, sopt.VarTypeStringSlice, nil)
+	if err != nil {
+		t.Errorf("Expected no error, but got %s", err.Error())
+		t.FailNow()
+	}
+
+	err = opt.SetOption("", "t", "tag", "Tags.", nil, false, sopt.VarTypeStringSlice, []any{"a", "b", "c"})
+	if err != nil {
+		t.Errorf("Expected no error, but got %s", err.Error())
+		t.FailNow()
+	}
+
+	opt.GetOption("tag").Separator = ","
+	opt.PrintHelp()
+	if len(opt.GetStringSlice("I")) != 1 {
+		t.Errorf("Expected default include path, but got %+v", opt.GetStringSlice("I"))
+		t.Fail()
+	}
+
+	err = opt.ParseArgs([]string{"-I", "a", "-I", "b", "--include=c", "--tag", "a,b", "-t", "c"})
+	if err != nil {
+		t.Errorf("Expected no error, but got %s", err.Error())
+		t.FailNow()
+	}
+
+	inc := opt.GetStringSlice("include")
+	if len(inc) != 3 || inc[0] != "a" || inc[2] != "c" {
+		t.Errorf("Expected [a b c], but got %+v", inc)
+		t.Fail()
+	}
+
+	tags := opt.GetStringSlice("tag")
+	if len(tags) != 3 {
+		t.Errorf("Expected 3 tags, but got %+v", tags)
+		t.Fail()
+	}
+
+	opt2 := sopt.New()
+	opt2.SetOption("", "t", "tag", "Tags.", nil, false, sopt.VarTypeStringSlice, []any{"a", "b"})
+	opt2.GetOption("t").Separator = ","
+	err = opt2.ParseArgs([]string{"--tag=a,x"})
+	if !errors.Is(err, sopt.ErrInvalidChoice) {
+		t.Errorf("Expected invalid choice, but got %v", err)
+		t.Fail()
+	}
+}
+
+func TestCount(t *testing.T) {
+	opt := sopt.New()
+	err := opt.SetOption("", "v", "verbose", "Show more details in output.", 0, false, sopt.VarTypeCount, nil)
+	if err != nil {
+		t.Errorf("Expected no error, but got %s", err.Error())
+		t.FailNow()
+	}
+
+	opt.SetOption("", "d", "debug", "Debug.", false, false, sopt.VarTypeBool, nil)
+	opt.SetPositional("FILE", "Full file path.", nil, false, sopt.VarTypeString)
+	opt.PrintHelp()
+	err = opt.ParseArgs([]string{"-vvd", "--verbose", "-v", "test.txt"})
+	if err != nil {
+		t.Errorf("Expected no error, but got %s", err.Error())
+		t.FailNow()
+	}
+
+	if opt.GetInt("v") != 4 || opt.GetPosString("FILE") != "test.txt" {
+		t.Errorf("Expected 4 and test.txt, but got %d and %s", opt.GetInt("v"), opt.GetPosString("FILE"))
+		t.Fail()
+	}
+
+	opt = sopt.New()
+	opt.SetOption("", "v", "verbose", "Show more details in output.", 0, false, sopt.VarTypeCount, nil)
+	err = opt.ParseArgs([]string{"--verbose=3"})
+	if err != nil || opt.GetInt("verbose") != 3 {
+		t.Errorf("Expected 3, but got %d (%v)", opt.GetInt("verbose"), err)
+		t.Fail()
+	}
+}
+
+func TestNegate(t *testing.T) {
+	opt := sopt.New()
+	opt.SetDefaultHelp()
+	err := opt.SetOption("", "c", "color", "Colourful output.", true, false, sopt.VarTypeBool, nil)
+	if err != nil {
+		t.Errorf("Expected no error, but got %s", err.Error())
+		t.FailNow()
+	}
+
+	var buf strings.Builder
+	opt.WriteHelp(&buf)
+	t.Log(buf.String())
+	if !strings.Contains(buf.String(), "--[no-]color") || strings.Contains(buf.String(), "--[no-]help") {
+		t.Errorf("Expected negatable color but not help in help text.")
+		t.Fail()
+	}
+
+	if opt.GetTristate("color") != sopt.TristateUnset || !opt.GetBool("color") {
+		t.Errorf("Expected unset color defaulting to true.")
+		t.Fail()
+	}
+
+	err = opt.ParseArgs([]string{"--no-color"})
+	if err != nil {
+		t.Errorf("Expected no error, but got %s", err.Error())
+		t.FailNow()
+	}
+
+	if opt.GetBool("color") || opt.GetTristate("color") != sopt.TristateFalse {
+		t.Errorf("Expected color to be explicitly false.")
+		t.Fail()
+	}
+
+	err = sopt.New().ParseArgs([]string{"--no-help"})
+	if !errors.Is(err, sopt.ErrUnknownOption) {
+		t.Errorf("Expected unknown option, but got %v", err)
+		t.Fail()
+	}
+}
+
+func TestDoubleDash(t *testing.T) {
+	opt := sopt.New()
+	opt.SetOption("", "v", "verbose", "Show more details in output.", false, false, sopt.VarTypeBool, nil)
+	opt.SetPositional("FILE", "Full file path.", nil, false, sopt.VarTypeString)
+	err := opt.ParseArgs([]string{"--", "-weird.txt", "-v", "--verbose"})
+	if err != nil {
+		t.Errorf("Expected no error, but got %s", err.Error())
+		t.FailNow()
+	}
+
+	if opt.GetPosString("FILE") != "-weird.txt" || opt.GetBool("v") {
+		t.Errorf("Expected -weird.txt and no verbose, but got %s and %v", opt.GetPosString("FILE"), opt.GetBool("v"))
+		t.Fail()
+	}
+
+	if len(opt.Remainder) != 2 || opt.Remainder[1] != "--verbose" {
+		t.Errorf("Expected remainder [-v --verbose], but got %+v", opt.Remainder)
+		t.Fail()
+	}
+}
+
+func TestParseModes(t *testing.T) {
+	opt := sopt.New()
+	opt.SetOption("", "v", "verbose", "Show more details in output.", false, false, sopt.VarTypeBool, nil)
+	opt.SetPositional("PROGRAM", "Program to run.", nil, false, sopt.VarTypeString)
+	opt.SetParseMode(sopt.ModePOSIX)
+	err := opt.ParseArgs([]string{"-v", "ls", "-v", "-l"})
+	if err != nil {
+		t.Errorf("Expected no error, but got %s", err.Error())
+		t.FailNow()
+	}
+
+	if opt.GetPosString("PROGRAM") != "ls" || len(opt.Remainder) != 2 {
+		t.Errorf("Expected ls with remainder [-v -l], but got %s with %+v", opt.GetPosString("PROGRAM"), opt.Remainder)
+		t.Fail()
+	}
+
+	opt = sopt.New()
+	opt.SetOption("", "v", "verbose", "Show more details in output.", false, false, sopt.VarTypeBool, nil)
+	opt.SetParseMode(sopt.ModeForwardUnknown)
+	err = opt.ParseArgs([]string{"--color=auto", "-v", "-la", "--verbose"})
+	if err != nil {
+		t.Errorf("Expected no error, but got %s", err.Error())
+		t.FailNow()
+	}
+
+	if !opt.GetBool("verbose") || len(opt.Remainder) != 2 || opt.Remainder[0] != "--color=auto" || opt.Remainder[1] != "-la" {
+		t.Errorf("Expected unknown options in remainder, but got %+v", opt.Remainder)
+		t.Fail()
+	}
+}
+
+func TestCollectErrors(t *testing.T) {
+	opt := sopt.New()
+	opt.SetParseMode(sopt.ModeCollectErrors)
+	opt.SetOption("", "p", "port", "Port to listen on.", nil, true, sopt.VarTypeInt, nil)
+	opt.SetOption("", "n", "name", "Name.", nil, true, sopt.VarTypeString, nil)
+	opt.SetOption("", "l", "level", "Log level.", "info", false, sopt.VarTypeString, []any{"info", "debug"})
+	opt.SetOption("", "", "host", "Host.", nil, true, sopt.VarTypeString, nil)
+	err := opt.ParseArgs([]string{"--level=trace", "--bogus", "-p", "x", "-q", "--host", "h"})
+	var list sopt.ParseErrors
+	if !errors.As(err, &list) {
+		t.Errorf("Expected ParseErrors, but got %v", err)
+		t.FailNow()
+	}
+
+	// The bad value of --port is reported once, not as a missing option as well.
+	want := []error{sopt.ErrInvalidChoice, sopt.ErrUnknownOption, sopt.ErrInvalidValue, sopt.ErrUnknownOption,
+		sopt.ErrMissingRequired}
+	if len(list) != len(want) {
+		t.Errorf("Expected %d errors, but got %d: %v", len(want), len(list), err)
+		t.FailNow()
+	}
+
+	for i, e := range want {
+		if !errors.Is(list[i], e) {
+			t.Errorf("Expected error %d to be %v, but got %v", i, e, list[i])
+			t.Fail()
+		}
+	}
+
+	if list[4].Option != "--name" {
+		t.Errorf("Expected --name to be missing, but got %s", list[4].Option)
+		t.Fail()
+	}
+
+	var ce *sopt.ChoiceError
+	if !errors.Is(err, sopt.ErrUnknownOption) || !errors.As(err, &ce) || ce.Value != "trace" {
+		t.Errorf("Expected the aggregate to match its errors, but got %v", err)
+		t.Fail()
+	}
+
+	err = opt.ParseArgs([]string{"-p", "80", "-n", "x", "--host", "h"})
+	if err != nil {
+		t.Errorf("Expected no error, but got %s", err.Error())
+		t.Fail()
+	}
+}
+
+func TestRequiredPositionals(t *testing.T) {
+	newOptions := func() *sopt.Options {
+		opt := sopt.New()
+		opt.SetPositional("SRC", "Source.", nil, true, sopt.VarTypeString)
+		opt.SetPositional("DST", "Destination.", nil, true, sopt.VarTypeString)
+		opt.SetPositional("EXTRA", "Extra files.", nil, false, sopt.VarTypePosStringSlice)
+		err := opt.SetArity("EXTRA", 0, 2)
+		if err != nil {
+			t.Errorf("Expected no error, but got %s", err.Error())
+			t.FailNow()
+		}
+
+		return opt
+	}
+
+	opt := newOptions()
+	if !strings.HasSuffix(opt.Usage(), " <SRC> <DST> [EXTRA...]") {
+		t.Errorf("Unexpected usage: %s", opt.Usage())
+		t.Fail()
+	}
+
+	err := opt.ParseArgs([]string{"a"})
+	var pe *sopt.ParseError
+	if !errors.As(err, &pe) || !errors.Is(err, sopt.ErrMissingPositional) || pe.Option != "DST" {
+		t.Errorf("Expected DST to be missing, but got %v", err)
+		t.Fail()
+	}
+
+	err = newOptions().ParseArgs([]string{"a", "b", "c", "d", "e"})
+	if !errors.As(err, &pe) || !errors.Is(err, sopt.ErrTooManyArguments) || pe.Index != 4 {
+		t.Errorf("Expected too many arguments at index 4, but got %v", err)
+		t.Fail()
+	}
+
+	opt = newOptions()
+	err = opt.ParseArgs([]string{"a", "b", "c"})
+	if err != nil || opt.GetPosString("DST") != "b" || len(opt.GetPosStringSlice("EXTRA")) != 1 {
+		t.Errorf("Expected DST and one extra, but got %v", err)
+		t.Fail()
+	}
+
+	err = opt.SetPositional("MORE", "More.", nil, false, sopt.VarTypeString)
+	if !errors.Is(err, sopt.ErrPositionalOrder) {
+		t.Errorf("Expected ErrPositionalOrder after a slice, but got %v", err)
+		t.Fail()
+	}
+
+	opt = sopt.New()
+	opt.SetPositional("SRC", "Source.", nil, false, sopt.VarTypeString)
+	err = opt.SetPositional("DST", "Destination.", nil, true, sopt.VarTypeString)
+	if !errors.Is(err, sopt.ErrPositionalOrder) {
+		t.Errorf("Expected ErrPositionalOrder after an optional argument, but got %v", err)
+		t.Fail()
+	}
+}
+
+func TestArity(t *testing.T) {
+	newOptions := func() *sopt.Options {
+		opt := sopt.New()
+		opt.SetPositional("FILES", "Files.", nil, false, sopt.VarTypePosStringSlice)
+		opt.SetArity("FILES", 2, 0)
+		return opt
+	}
+
+	opt := newOptions()
+	err := opt.SetArity("FILES", 3, 2)
+	if !errors.Is(err, sopt.ErrArity) {
+		t.Errorf("Expected ErrArity, but got %v", err)
+		t.Fail()
+	}
+
+	if !strings.HasSuffix(opt.Usage(), " <FILES...>") {
+		t.Errorf("Unexpected usage: %s", opt.Usage())
+		t.Fail()
+	}
+
+	err = opt.ParseArgs([]string{"a"})
+	if !errors.Is(err, sopt.ErrTooFewArguments) {
+		t.Errorf("Expected ErrTooFewArguments, but got %v", err)
+		t.Fail()
+	}
+
+	err = newOptions().ParseArgs([]string{})
+	if !errors.Is(err, sopt.ErrMissingPositional) {
+		t.Errorf("Expected ErrMissingPositional, but got %v", err)
+		t.Fail()
+	}
+
+	err = newOptions().ParseArgs([]string{"a", "b", "c"})
+	if err != nil {
+		t.Errorf("Expected no error, but got %s", err.Error())
+		t.Fail()
+	}
+}
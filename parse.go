@@ -1,41 +1,60 @@
 package sopt
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
-	"strconv"
+	"os/signal"
+	"sort"
 	"strings"
+	"syscall"
 )
 
 // ShowOptions shows the values of all options. Used for debugging.
+//
+// Deprecated: use WriteReport, which also shows where the values came from.
 func (opt *Options) ShowOptions() {
-	for _, o := range opt.short {
-		fmt.Printf("-%s: %v (%v)\n", o.ShortName, o.Value, o.Default)
-	}
-
-	for _, o := range opt.long {
-		fmt.Printf("--%s: %v (%v)\n", o.LongName, o.Value, o.Default)
-	}
+	opt.WriteReport(opt.getOutput())
 }
 
-// Parse the command line arguments from os.Args. Internally it calls ParseArgs.
+// Parse the command line arguments from os.Args, or the IO set with SetIO. Internally it calls Execute with
+// a context cancelled on SIGINT or SIGTERM. The signals get their default behaviour back before a command
+// without FuncCtx is called, so it can still be interrupted.
 // - If default help is defined, it will print the help message after parsing when "-h" or "--help" is supplied,
 // then os.Exit(0).
 // - If emptyhelp is true and no arguments are supplied, it will print the help message and os.Exit(0).
+//
+// With an Exit function set in the IO, Parse returns ErrHelpRequested if that function returns.
 func (opt *Options) Parse(emptyhelp bool) error {
-	if len(os.Args) == 1 && emptyhelp {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	err := opt.Execute(context.WithValue(ctx, stopKey{}, stop), emptyhelp)
+	if errors.Is(err, ErrHelpRequested) {
+		opt.getIO().exit(0)
+	}
+
+	return err
+}
+
+// Execute parses the arguments of the IO set with SetIO, or os.Args, with ParseArgsContext. Instead of
+// exiting, it prints the help message and returns ErrHelpRequested when "-h" or "--help" is supplied with
+// default help defined, or when emptyhelp is true and no arguments are supplied.
+func (opt *Options) Execute(ctx context.Context, emptyhelp bool) error {
+	args := opt.getIO().args()
+	if len(args) == 0 && emptyhelp {
 		opt.PrintHelp()
-		os.Exit(0)
+		return ErrHelpRequested
 	}
 
-	err := opt.ParseArgs(os.Args[1:])
+	r, err := opt.parseResult(ctx, args, true)
 	if err != nil {
 		return err
 	}
 
-	if opt.hashelp && opt.GetBool("h") {
-		opt.PrintHelp()
-		os.Exit(0)
+	if r.wantsHelp() {
+		r.leaf().PrintHelp()
+		return ErrHelpRequested
 	}
 
 	return nil
@@ -48,8 +67,13 @@ func (opt *Options) Parse(emptyhelp bool) error {
 //
 // Single- and double-dash options found before any tool commands are parsed for the Options structure.
 //
-// Tool commands break the parsing off, and calls the command with the remaining arguments after running
-//  any handlers for the pre-command options.
+// Tool commands continue the parsing with the command's own Options, so commands can be nested
+// ("tool remote add --name x url"). Options not defined by a command are looked up in its parents,
+// letting global flags appear anywhere in the tree. When all levels are parsed and their required
+// options checked, the innermost command is called with its remaining arguments. Commands without any
+// definitions of their own only take the options of their parents, and get all other arguments, including
+// unknown options, in order. Commands with RawArgs set get the arguments following the command name as
+// they are.
 // Options criteria:
 // - Short options start with a single dash ("-").
 // - Short boolean options don't need to take a value.
@@ -58,136 +82,306 @@ func (opt *Options) Parse(emptyhelp bool) error {
 // - Falsy values are everything else.
 // - Short options can be combined ("-a -b" can be written as "-ab").
 // - Combined short options allow only the last one to take a value. The ones before must be booleans.
+// - Counter options count each time they're given ("-vvv" is 3), unless given a value ("--verbose=3").
 //
 // - Long options start with a double dash ("--").
 // - Long options are followed by either whitespace or an equal sign ("--foo bar" or "--foo=bar").
+// - Long boolean options can be negated with a "no-" prefix ("--no-foo" sets "foo" to false).
+//
+// - String slice options can be repeated ("-I a -I b --include=c"), each occurrence adding to the slice.
+// - String slice options with a Separator split each value ("--tag a,b,c").
+//
+// A double dash ("--") ends option parsing, and the arguments after it are positional even if they start
+// with a dash. See SetParseMode for other ways to end option parsing, and for handling unknown options.
+//
+// Constraints between options, such as MutuallyExclusive, are checked after required options.
+//
+// Required positional arguments must be supplied, and positional slices must get the number of values
+// set with SetArity.
+//
+// Options and positional arguments not supplied on the command line are taken from their environment
+// variables, then from any configuration file, before falling back to their defaults. In ModePrompt,
+// missing required ones are asked for.
+//
+// Errors returned by the command are returned prefixed with the command path.
+//
+// The values are kept for the getters of Options until the next ParseArgs or Reset. Use ParseResult to
+// keep the values of each parse apart.
 func (opt *Options) ParseArgs(args []string) error {
+	return opt.ParseArgsContext(context.Background(), args)
+}
+
+// ParseArgsContext parses the supplied string slice as CLI arguments like ParseArgs, passing ctx on to
+// commands defined with SetCommandCtx.
+func (opt *Options) ParseArgsContext(ctx context.Context, args []string) error {
+	_, err := opt.parseResult(ctx, args, true)
+	return err
+}
+
+// ParseResult parses the supplied string slice as CLI arguments like ParseArgsContext, but only returns
+// the values in a Result instead of keeping them for the getters of Options. Commands get the Result with
+// ResultFrom, and a nil *Options, as the getters would show another parse. On errors, the Result holds
+// what was parsed so far.
+func (opt *Options) ParseResult(ctx context.Context, args []string) (*Result, error) {
+	return opt.parseResult(ctx, args, false)
+}
+
+// parseResult parses args into a new Result, which is kept for the getters if keep is true, and runs
+// the selected command.
+func (opt *Options) parseResult(ctx context.Context, args []string, keep bool) (*Result, error) {
+	r := newResult(opt)
+	if keep {
+		opt.setLatest(r)
+	}
+
+	if opt.completion && len(args) > 0 && args[0] == completeCommand {
+		opt.printCompletion(args[1:])
+		return r, nil
+	}
+
+	if opt.getParseMode()&ModeResponseFiles != 0 {
+		list, err := expandResponseFiles(args)
+		if err != nil {
+			return r, err
+		}
+
+		args = list
+	}
+
+	// Values taken by options are blanked out while parsing, so the caller's slice is left alone.
+	cmd, cmdargs, err := opt.parse(r, append([]string{}, args...), 0)
+	r.Command, r.Args = cmd, cmdargs
+	if keep {
+		for _, o := range r.path {
+			o.Remainder = r.remainder[o]
+		}
+	}
+
+	if err != nil {
+		return r, err
+	}
+
+	// Configuration files may set options of any level, so they're all read first. Entries of the
+	// innermost levels take precedence, and files read while parsing over those of LoadConfig.
+	cfgs := []configValues{}
+	for i := len(r.path) - 1; i >= 0; i-- {
+		o := r.path[i]
+		cfg, err := o.loadConfigFile(r)
+		if err != nil {
+			return r, err
+		}
+
+		cfgs = append(cfgs, cfg, o.config)
+	}
+
+	help := r.wantsHelp()
+	for i := len(r.path) - 1; i >= 0; i-- {
+		o := r.path[i]
+		err = o.applyEnv(r)
+		if err != nil {
+			return r, err
+		}
+
+		err = o.applyConfig(r, cfgs)
+		if err != nil {
+			return r, err
+		}
+
+		// Asking for help is never an error, whatever else is missing.
+		if !help {
+			err = o.validate(r)
+			if err != nil {
+				return r, err
+			}
+		}
+
+		o.applyBinds(r)
+	}
+
+	err = r.collectedErrors()
+	if err != nil {
+		return r, err
+	}
+
+	if cmd == nil || r.wantsHelp() {
+		return r, nil
+	}
+
+	// The getters of Options only see the values kept for them, so commands of ParseResult get none.
+	var cmdopt *Options
+	if keep {
+		cmdopt = cmd.Options
+	}
+
+	err = cmd.run(context.WithValue(ctx, resultKey{}, r), cmdopt, cmdargs)
+	if err != nil {
+		return r, fmt.Errorf("%s: %w", cmd.Path(), err)
+	}
+
+	return r, nil
+}
+
+// parse fills in the values of options and positional arguments at this level, descending into any
+// command found. The innermost command and its arguments are returned. Base is the index of the first
+// argument in the full command line, for errors.
+func (opt *Options) parse(r *Result, args []string, base int) (*Command, []string, error) {
+	r.path = append(r.path, opt)
+	mode := opt.getParseMode()
+	// Commands without definitions of their own take the options of their parents, and get everything
+	// else as it is.
+	if opt.parent != nil && opt.empty() {
+		mode |= ModeForwardUnknown
+	}
+
 	unknown := []string{}
 	pos := opt.positional
+	// positional stores an argument in the next positional argument, or adds it to the remainder.
+	positional := func(i int, arg string) error {
+		if len(pos) == 0 {
+			unknown = append(unknown, arg)
+			return nil
+		}
+
+		if pos[0].MaxArgs > 0 && r.count(pos[0]) >= pos[0].MaxArgs {
+			return opt.fail(r, newParseError(base+i, arg, pos[0].Placeholder, ErrTooManyArguments))
+		}
+
+		err := r.set(pos[0], arg, Source{Kind: SourceCommandLine, Index: base + i})
+		if err != nil {
+			return opt.fail(r, newParseError(base+i, arg, pos[0].Placeholder, err))
+		}
+
+		// Slices swallow the rest of the positional arguments.
+		if pos[0].Type != VarTypePosStringSlice {
+			pos = pos[1:]
+		}
+		return nil
+	}
+
+	stop := false
 	for i, arg := range args {
 		if arg == "" {
 			continue
 		}
 
-		cmd := opt.commands[arg]
-		if cmd != nil {
-			fn := cmd.Func
-			if fn == nil {
-				return fmt.Errorf("%s: %s", arg, ErrMissingFunc)
+		src := Source{Kind: SourceCommandLine, Index: base + i}
+
+		if stop {
+			err := positional(i, arg)
+			if err != nil {
+				return nil, nil, err
 			}
 
-			fn(args[i+1:])
-			return nil
+			continue
 		}
 
-		if len(arg) < 2 && len(pos) < 0 {
-			unknown = append(unknown, arg)
+		if arg == "--" {
+			stop = true
 			continue
 		}
 
-		//
-		// Long options
-		//
+		cmd := opt.GetCommand(arg)
+		if cmd != nil {
+			r.remainder[opt] = unknown
+			if cmd.RawArgs {
+				return cmd, args[i+1:], nil
+			}
 
-		if arg[0] == '-' && arg[1] == '-' {
-			arg = arg[2:]
-			if arg == "" {
-				return ErrEmptyLong
+			sub, subargs, err := cmd.Options.parse(r, args[i+1:], base+i+1)
+			if err != nil {
+				return nil, nil, err
 			}
 
-			a := splitOption(arg)
-			o, ok := opt.long[a[0]]
-			if ok {
-				switch o.Type {
-				case VarTypeBool:
-					t, v := isTruthy(a[1])
-					// We have the form "--option=value"
-					if t {
-						o.Value = v
-						continue
-					}
+			if sub == nil {
+				return cmd, r.remainder[cmd.Options], nil
+			}
 
-					if len(args) > i+1 {
-						t, v = isTruthy(args[i+1])
-						// We have the form "--option value"
-						if t {
-							o.Value = v
-							args[i+1] = ""
-							continue
-						}
-					}
+			return sub, subargs, nil
+		}
 
-					// It's a standalone boolean option, so just set it to teue. Phew!
-					o.Value = true
+		//
+		// Long options
+		//
 
-				case VarTypeString:
-					if a[1] != "" {
-						o.Value = a[1]
-						continue
-					}
+		if strings.HasPrefix(arg, "--") {
+			a := splitOption(arg[2:])
+			o := opt.lookupLong(a[0])
+			if o == nil {
+				o = opt.lookupNegated(a[0])
+				if o == nil && mode&ModeForwardUnknown != 0 {
+					unknown = append(unknown, args[i])
+					continue
+				}
 
-					if len(args) > i+1 {
-						o.Value = args[i+1]
-						args[i+1] = ""
-						continue
+				if o == nil {
+					err := opt.fail(r, opt.unknownError(base+i, arg, "--"+a[0], ErrUnknownOption))
+					if err != nil {
+						return nil, nil, err
 					}
 
-					return fmt.Errorf("--%s: %w", o.LongName, ErrMissingArgument)
-
-				case VarTypeInt:
-					if a[1] != "" {
-						v, err := strconv.Atoi(a[1])
-						if err != nil {
-							return err
-						}
+					continue
+				}
 
-						o.Value = v
-						continue
-					}
+				// We have the form "--no-option" or "--no-option=value"
+				t, v := isTruthy(a[1])
+				r.put(o, t && !v, src)
+				continue
+			}
 
-					if len(args) > i+1 {
-						v, err := strconv.Atoi(args[i+1])
-						if err != nil {
-							return err
-						}
+			if o.Type == VarTypeBool {
+				t, v := isTruthy(a[1])
+				// We have the form "--option=value"
+				if t {
+					r.put(o, v, src)
+					continue
+				}
 
-						o.Value = v
+				if len(args) > i+1 {
+					t, v = isTruthy(args[i+1])
+					// We have the form "--option value"
+					if t {
+						r.put(o, v, src)
 						args[i+1] = ""
 						continue
 					}
+				}
 
-					return fmt.Errorf("--%s: %w", o.LongName, ErrMissingArgument)
+				// It's a standalone boolean option, so just set it to true. Phew!
+				r.put(o, true, src)
+				continue
+			}
 
-				case VarTypeFloat:
-					if a[1] != "" {
-						v, err := strconv.ParseFloat(a[1], 64)
-						if err != nil {
-							return err
-						}
+			if o.Type == VarTypeCount && a[1] == "" {
+				r.increment(o, src)
+				continue
+			}
 
-						o.Value = v
-						continue
+			if a[1] == "" && o.boolValue() {
+				a[1] = "true"
+			}
+
+			if a[1] == "" {
+				if len(args) <= i+1 {
+					err := opt.fail(r, newParseError(base+i, arg, "--"+o.LongName, ErrMissingArgument))
+					if err != nil {
+						return nil, nil, err
 					}
 
-					if len(args) > i+1 {
-						v, err := strconv.ParseFloat(args[i+1], 64)
-						if err != nil {
-							return err
-						}
+					continue
+				}
 
-						o.Value = v
-						args[i+1] = ""
-						continue
-					}
+				a[1] = args[i+1]
+				args[i+1] = ""
+			}
 
-					return fmt.Errorf("--%s: %w", o.LongName, ErrMissingArgument)
+			err := r.set(o, a[1], src)
+			if err != nil {
+				err = opt.fail(r, newParseError(base+i, arg, "--"+a[0], err))
+				if err != nil {
+					return nil, nil, err
+				}
+			}
 
-				default:
-					return fmt.Errorf("--%s: %w", o.LongName, ErrUnknownType)
-				} // switch o.Type
-			} else {
-				return fmt.Errorf("--%s: %w", a[0], ErrUnknownOption)
-			} // if long option is defined
 			continue
 		} // if long option
 
@@ -195,173 +389,219 @@ func (opt *Options) ParseArgs(args []string) error {
 		// Short options
 		//
 
-		if arg[0] == '-' {
-			s := arg[1:]
-			a := splitOption(s)
-			if a[1] != "" {
-				s = a[0]
+		if arg[0] == '-' && len(arg) > 1 {
+			a := splitOption(arg[1:])
+			s := a[0]
+			if mode&ModeForwardUnknown != 0 && !opt.knownShorts(s) {
+				unknown = append(unknown, arg)
+				continue
 			}
 
-			for _, c := range s {
-				o, ok := opt.short[string(c)]
-				if ok {
-					switch o.Type {
-					case VarTypeBool:
-						if a[0] == string(c) && a[1] != "" {
-							_, v := isTruthy(a[1])
-							o.Value = v
-							continue
-						}
-
-						if len(args) > i+1 {
-							t, v := isTruthy(args[i+1])
-							if t {
-								o.Value = v
-								args[i+1] = ""
-								continue
-							}
-						}
+			for n, c := range s {
+				o := opt.lookupShort(string(c))
+				if o == nil {
+					// The rest of the argument could be the value of the unknown option.
+					err := opt.fail(r, opt.unknownError(base+i, arg, "-"+string(c), ErrUnknownOption))
+					if err != nil {
+						return nil, nil, err
+					}
 
-						o.Value = true
+					break
+				}
 
-					case VarTypeString:
-						if a[0] == string(c) && a[1] != "" {
-							o.Value = a[1]
-							continue
-						}
+				// Only the last of combined short options can take a value.
+				last := n+len(string(c)) == len(s)
+				if o.Type == VarTypeBool {
+					if last && a[1] != "" {
+						_, v := isTruthy(a[1])
+						r.put(o, v, src)
+						continue
+					}
 
-						if len(args) > i+1 {
-							o.Value = args[i+1]
+					if len(args) > i+1 {
+						t, v := isTruthy(args[i+1])
+						if t {
+							r.put(o, v, src)
 							args[i+1] = ""
 							continue
 						}
+					}
 
-						return fmt.Errorf("-%c: %w", c, ErrMissingArgument)
-
-					case VarTypeInt:
-						if a[0] == string(c) && a[1] != "" {
-							v, err := strconv.Atoi(a[1])
-							if err != nil {
-								return err
-							}
-
-							o.Value = v
-							continue
-						}
-
-						if len(args) > i+1 {
-							v, err := strconv.Atoi(args[i+1])
-							if err != nil {
-								return err
-							}
-
-							o.Value = v
-							args[i+1] = ""
-							continue
-						}
+					r.put(o, true, src)
+					continue
+				}
 
-						return fmt.Errorf("-%c: %w", c, ErrMissingArgument)
+				v := a[1]
+				if o.Type == VarTypeCount && (!last || v == "") {
+					r.increment(o, src)
+					continue
+				}
 
-					case VarTypeFloat:
-						if a[0] == string(c) && a[1] != "" {
-							v, err := strconv.ParseFloat(a[1], 64)
-							if err != nil {
-								return err
-							}
+				if (!last || v == "") && o.boolValue() {
+					v = "true"
+				}
 
-							o.Value = v
-							continue
+				if !last || v == "" {
+					if len(args) <= i+1 {
+						err := opt.fail(r, newParseError(base+i, arg, "-"+string(c), ErrMissingArgument))
+						if err != nil {
+							return nil, nil, err
 						}
 
-						if len(args) > i+1 {
-							v, err := strconv.ParseFloat(args[i+1], 64)
-							if err != nil {
-								return err
-							}
-
-							o.Value = v
-							args[i+1] = ""
-							continue
-						}
+						break
+					}
 
-						return fmt.Errorf("-%c: %w", c, ErrMissingArgument)
+					v = args[i+1]
+					args[i+1] = ""
+				}
 
-					} // switch o.Type
-				} else {
-					return fmt.Errorf("-%c: %w", c, ErrUnknownOption)
-				} // if short option is defined
+				err := r.set(o, v, src)
+				if err != nil {
+					err = opt.fail(r, newParseError(base+i, arg, "-"+string(c), err))
+					if err != nil {
+						return nil, nil, err
+					}
+				}
 			} // range s
 			continue
 		} // if short option
 
-		if len(pos) > 0 {
-			switch pos[0].Type {
-			case VarTypeBool:
-				t, v := isTruthy(arg)
-				if t {
-					pos[0].Value = v
-				} else {
-					pos[0].Value = false
-				}
+		// A stray word where only commands are expected is most likely a mistyped command.
+		// The rest of the arguments can't be known to belong here, so parsing stops.
+		if len(pos) == 0 && len(unknown) == 0 && len(opt.commands) > 0 && mode&ModeStrictCommands != 0 {
+			err := opt.fail(r, opt.unknownError(base+i, arg, arg, ErrUnknownCommand))
+			if err != nil {
+				return nil, nil, err
+			}
 
-			case VarTypeString:
-				pos[0].Value = arg
+			break
+		}
 
-			case VarTypePosStringSlice:
-				if pos[0].Value == nil {
-					pos[0].Value = []string{}
-				}
+		err := positional(i, arg)
+		if err != nil {
+			return nil, nil, err
+		}
 
-				pos[0].Value = append(pos[0].Value.([]string), arg)
-				continue
+		if mode&ModePOSIX != 0 {
+			stop = true
+		}
+	}
 
-			case VarTypeInt:
-				v, err := strconv.Atoi(arg)
-				if err != nil {
-					return err
-				}
+	r.remainder[opt] = unknown
+	return nil, nil, nil
+}
 
-				pos[0].Value = v
+// validate prompts for missing values in ModePrompt, then checks required options, positional arguments
+// and constraints.
+func (opt *Options) validate(r *Result) error {
+	if opt.getParseMode()&ModePrompt != 0 {
+		err := opt.prompt(r)
+		if err != nil {
+			return err
+		}
+	}
 
-			case VarTypeFloat:
-				v, err := strconv.ParseFloat(arg, 64)
-				if err != nil {
-					return err
-				}
+	err := opt.checkRequired(r)
+	if err != nil {
+		return err
+	}
+
+	return opt.checkConstraints(r)
+}
 
-				pos[0].Value = v
+// checkRequired returns an error for the first required option or positional argument without a value,
+// or positional slice with the wrong number of values, in the order they were defined.
+// In ModeCollectErrors all of them are collected instead.
+func (opt *Options) checkRequired(r *Result) error {
+	for _, g := range opt.GetGroups() {
+		for _, o := range g.options {
+			if !o.Required || r.isSet(o) || r.failed(opt, o) {
+				continue
 			}
 
-			pos = pos[1:]
+			err := opt.fail(r, newParseError(-1, "", o.name(), ErrMissingRequired))
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, o := range opt.positional {
+		var sentinel error
+		switch {
+		case o.Required && !r.isSet(o) && !r.failed(opt, o):
+			sentinel = ErrMissingPositional
+		case !r.isSet(o):
+			continue
+		case r.count(o) < o.MinArgs:
+			sentinel = ErrTooFewArguments
+		case o.MaxArgs > 0 && r.count(o) > o.MaxArgs:
+			sentinel = ErrTooManyArguments
+		default:
 			continue
 		}
 
-		unknown = append(unknown, arg)
+		err := opt.fail(r, newParseError(-1, "", o.Placeholder, sentinel))
+		if err != nil {
+			return err
+		}
 	}
 
-	// Parse the remaining unknown arguments as positional arguments, if applicable.
-	for _, arg := range unknown {
-		if arg != "" {
-		}
-		unknown = unknown[1:]
+	return nil
+}
+
+// failed returns true if an error was collected for an option, under any of the names it can be given by,
+// so it isn't reported as missing as well.
+func (r *Result) failed(opt *Options, o *Option) bool {
+	names := []string{o.Placeholder}
+	if o.LongName != "" {
+		names = append(names, "--"+o.LongName, "--no-"+o.LongName)
 	}
-	opt.Remainder = unknown
 
-	for _, o := range opt.short {
-		if o.Required && o.Value == nil {
-			return fmt.Errorf("-%s: %w", o.ShortName, ErrMissingRequired)
-		}
+	if o.ShortName != "" {
+		names = append(names, "-"+o.ShortName)
+	}
+
+	if env := opt.envName(o); env != "" {
+		names = append(names, "$"+env)
 	}
 
-	for _, o := range opt.long {
-		if o.Required && o.Value == nil {
-			return fmt.Errorf("--%s: %w", o.LongName, ErrMissingRequired)
+	for _, pe := range r.errs {
+		for _, n := range names {
+			if n != "" && pe.Option == n {
+				return true
+			}
 		}
 	}
 
+	return false
+}
+
+// fail returns the error, or collects it in r and returns nil in ModeCollectErrors.
+func (opt *Options) fail(r *Result, pe *ParseError) error {
+	if opt.getParseMode()&ModeCollectErrors == 0 {
+		return pe
+	}
+
+	r.errs = append(r.errs, pe)
 	return nil
 }
 
+// collectedErrors returns the errors collected at all levels of the selected commands, or nil.
+func (r *Result) collectedErrors() error {
+	if len(r.errs) == 0 {
+		return nil
+	}
+
+	list := append(ParseErrors{}, r.errs...)
+	// Errors without an argument go last.
+	sort.SliceStable(list, func(i, j int) bool {
+		a, b := list[i].Index, list[j].Index
+		return a != -1 && (b == -1 || a < b)
+	})
+	return list
+}
+
 func splitOption(arg string) []string {
 	a := strings.SplitN(arg, "=", 2)
 	if len(a) == 1 {
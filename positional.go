@@ -4,11 +4,19 @@ import "fmt"
 
 // SetPositional sets a positional argument.
 // Arguments which aren't long or short options or tool commands are considered positional.
+// Required positional arguments must come before optional ones, and a slice must come last.
 func (opt *Options) SetPositional(placeholder, help string, defaultvalue any, required bool, t uint8) error {
 	if len(placeholder) == 0 {
 		return fmt.Errorf("%w", ErrNoPlaceholder)
 	}
 
+	if len(opt.positional) > 0 {
+		last := opt.positional[len(opt.positional)-1]
+		if last.Type == VarTypePosStringSlice || required && !last.Required {
+			return fmt.Errorf("%s: %w", placeholder, ErrPositionalOrder)
+		}
+	}
+
 	o := &Option{
 		Placeholder: placeholder,
 		Help:        help,
@@ -17,45 +25,64 @@ func (opt *Options) SetPositional(placeholder, help string, defaultvalue any, re
 		Required:    required,
 	}
 
+	err := checkType(placeholder, t, defaultvalue)
+	if err != nil {
+		return err
+	}
+
 	opt.positional = append(opt.positional, o)
 	opt.posmap[placeholder] = o
 	return nil
 }
 
-// GetPosBool returns a positional boolean's value.
-func (opt *Options) GetPosBool(placeholder string) bool {
+// SetArity sets the minimum and maximum number of values of a positional string slice. A maximum of 0
+// means no limit. A minimum above 0 makes the argument required.
+func (opt *Options) SetArity(placeholder string, min, max int) error {
 	o := opt.posmap[placeholder]
-	if o == nil {
-		return false
+	if o == nil || o.Type != VarTypePosStringSlice || min < 0 || max < 0 || max > 0 && min > max {
+		return fmt.Errorf("%s: %w", placeholder, ErrArity)
 	}
 
-	if o.Value == nil {
-		if o.Default != nil {
-			return o.Default.(bool)
+	if min > 0 && !o.Required {
+		for _, p := range opt.positional {
+			if p != o && !p.Required {
+				return fmt.Errorf("%s: %w", placeholder, ErrPositionalOrder)
+			}
 		}
 
-		return false
+		o.Required = true
 	}
 
-	return o.Value.(bool)
+	o.MinArgs = min
+	o.MaxArgs = max
+	return nil
 }
 
-// GetPosString returns a positional string's value.
-func (opt *Options) GetPosString(placeholder string) string {
+// GetPositional returns a pointer to a positional argument.
+func (opt *Options) GetPositional(placeholder string) *Option {
+	return opt.posmap[placeholder]
+}
+
+// GetPosBool returns a positional boolean's value.
+func (opt *Options) GetPosBool(placeholder string) bool {
 	o := opt.posmap[placeholder]
 	if o == nil {
-		return ""
+		return false
 	}
 
-	if o.Value == nil {
-		if o.Default != nil {
-			return o.Default.(string)
-		}
+	v, _ := value[bool](opt.latest(), o)
+	return v
+}
 
+// GetPosString returns a positional string's value.
+func (opt *Options) GetPosString(placeholder string) string {
+	o := opt.posmap[placeholder]
+	if o == nil {
 		return ""
 	}
 
-	return o.Value.(string)
+	v, _ := value[string](opt.latest(), o)
+	return v
 }
 
 // GetPosStringSlice returns a positional string slice's values.
@@ -65,13 +92,6 @@ func (opt *Options) GetPosStringSlice(placeholder string) []string {
 		return nil
 	}
 
-	if o.Value == nil {
-		if o.Default != nil {
-			return o.Default.([]string)
-		}
-
-		return nil
-	}
-
-	return o.Value.([]string)
+	v, _ := value[[]string](opt.latest(), o)
+	return v
 }
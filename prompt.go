@@ -0,0 +1,182 @@
+package sopt
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// prompter reads values for missing required options and positional arguments.
+type prompter struct {
+	in *bufio.Reader
+	// tty is true when reading from the terminal of the process, whose echo can be turned off.
+	tty bool
+	out io.Writer
+}
+
+// newPrompter returns a prompter reading from the IO's Stdin, or from os.Stdin if it's a terminal.
+// It returns nil if there's nothing to read from.
+func (opt *Options) newPrompter() *prompter {
+	p := &prompter{out: opt.getErrOutput()}
+	sys := opt.getIO()
+	if sys.Stdin != nil {
+		p.in = bufio.NewReader(sys.Stdin)
+		return p
+	}
+
+	fi, err := os.Stdin.Stat()
+	if err != nil || fi.Mode()&os.ModeCharDevice == 0 {
+		return nil
+	}
+
+	p.in = bufio.NewReader(os.Stdin)
+	p.tty = true
+	return p
+}
+
+// prompt asks for the required options and positional arguments without a value, until each gets a
+// valid one. Options are left unset when the input ends.
+func (opt *Options) prompt(r *Result) error {
+	list := []*Option{}
+	for _, g := range opt.GetGroups() {
+		list = append(list, g.options...)
+	}
+	list = append(list, opt.positional...)
+
+	for _, o := range list {
+		if !o.Required || r.isSet(o) {
+			continue
+		}
+
+		if r.prompter == nil {
+			r.prompter = opt.newPrompter()
+			if r.prompter == nil {
+				return nil
+			}
+		}
+
+		err := r.prompter.ask(r, o)
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ask prompts for one option until it gets a valid value.
+func (p *prompter) ask(r *Result, o *Option) error {
+	for {
+		label := o.Help
+		if label == "" {
+			label = o.name()
+		} else {
+			label = strings.TrimSuffix(label, ".") + " (" + o.name() + ")"
+		}
+
+		if len(o.Choices) > 0 {
+			fmt.Fprintf(p.out, "%s:\n", label)
+			for i, c := range o.Choices {
+				fmt.Fprintf(p.out, "  %d) %s\n", i+1, formatValue(c))
+			}
+			fmt.Fprintf(p.out, "Choose 1-%d: ", len(o.Choices))
+		} else if o.Type == VarTypeBool {
+			fmt.Fprintf(p.out, "%s [y/n]: ", label)
+		} else {
+			fmt.Fprintf(p.out, "%s: ", label)
+		}
+
+		s, err := p.readLine(o.Secret)
+		if err == ErrEchoOn {
+			fmt.Fprintln(p.out)
+			return fmt.Errorf("%s: %w", o.name(), err)
+		}
+
+		if err != nil && (s == "" || !errors.Is(err, io.EOF)) {
+			fmt.Fprintln(p.out)
+			return err
+		}
+
+		if s == "" {
+			continue
+		}
+
+		n, err := strconv.Atoi(s)
+		if len(o.Choices) > 0 && err == nil && n >= 1 && n <= len(o.Choices) {
+			s = formatValue(o.Choices[n-1])
+		}
+
+		if o.Type == VarTypeBool {
+			if ok, _ := isTruthy(s); !ok {
+				fmt.Fprintf(p.out, "Please answer yes or no.\n")
+				continue
+			}
+		}
+
+		err = p.store(r, o, s)
+		if err != nil {
+			fmt.Fprintf(p.out, "Error: %s\n", err)
+			continue
+		}
+
+		return nil
+	}
+}
+
+// store sets the option to the input. Positional slices take each word as a value.
+func (p *prompter) store(r *Result, o *Option, s string) error {
+	src := Source{Kind: SourcePrompt}
+	if o.Type != VarTypePosStringSlice {
+		return r.set(o, s, src)
+	}
+
+	words := strings.Fields(s)
+	if len(words) < o.MinArgs || o.MaxArgs > 0 && len(words) > o.MaxArgs {
+		return fmt.Errorf("%s: %w", o.name(), ErrArity)
+	}
+
+	for _, w := range words {
+		err := r.set(o, w, src)
+		if err != nil {
+			delete(r.values, o)
+			delete(r.sources, o)
+			return err
+		}
+	}
+
+	return nil
+}
+
+// readLine reads a line of input without the line ending. Secret input isn't echoed on terminals, and
+// isn't read at all if the echo can't be turned off.
+func (p *prompter) readLine(secret bool) (string, error) {
+	if secret && p.tty {
+		if stty("-echo") != nil {
+			return "", ErrEchoOn
+		}
+
+		defer func() {
+			stty("echo")
+			fmt.Fprintln(p.out)
+		}()
+	}
+
+	s, err := p.in.ReadString('\n')
+	return strings.TrimRight(s, "\r\n"), err
+}
+
+// stty changes the settings of the terminal on standard input.
+func stty(arg string) error {
+	cmd := exec.Command("stty", arg)
+	cmd.Stdin = os.Stdin
+	return cmd.Run()
+}
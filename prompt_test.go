@@ -0,0 +1,78 @@
+package sopt_test
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/grimdork/sopt"
+)
+
+func TestPrompt(t *testing.T) {
+	var out bytes.Buffer
+	opt := sopt.New()
+	opt.SetParseMode(sopt.ModePrompt)
+	opt.SetOption("", "p", "port", "Port to listen on.", nil, true, sopt.VarTypeInt, nil)
+	opt.SetOption("", "l", "level", "Log level.", nil, true, sopt.VarTypeString, []any{"info", "debug"})
+	opt.SetOption("", "", "password", "Password.", nil, true, sopt.VarTypeString, nil)
+	opt.SetOption("", "n", "name", "Name.", nil, true, sopt.VarTypeString, nil)
+	opt.SetPositional("FILES", "Files to serve.", nil, true, sopt.VarTypePosStringSlice)
+	opt.GetOption("password").Secret = true
+	in := strings.NewReader("http\n\n8080\n3\n2\nhunter2\na b\n")
+	opt.SetIO(&sopt.IO{Stdin: in, Stderr: &out})
+	err := opt.ParseArgs([]string{"--name", "x"})
+	if err != nil {
+		t.Errorf("Expected no error, but got %s", err.Error())
+		t.FailNow()
+	}
+
+	if opt.GetInt("port") != 8080 || opt.GetString("level") != "debug" || opt.GetString("password") != "hunter2" {
+		t.Errorf("Expected prompted values, but got %d, %s and %s", opt.GetInt("port"), opt.GetString("level"),
+			opt.GetString("password"))
+		t.Fail()
+	}
+
+	if len(opt.GetPosStringSlice("FILES")) != 2 || opt.Source("port").Kind != sopt.SourcePrompt {
+		t.Errorf("Expected two files and a prompted port, but got %v and %s", opt.GetPosStringSlice("FILES"),
+			opt.Source("port"))
+		t.Fail()
+	}
+
+	prompts := out.String()
+	for _, s := range []string{"Port to listen on (--port): ", "Error: --port: invalid value", "  2) debug\nChoose 1-2: ",
+		"Files to serve (FILES): "} {
+		if !strings.Contains(prompts, s) {
+			t.Errorf("Expected %q in the prompts, but got:\n%s", s, prompts)
+			t.Fail()
+		}
+	}
+
+	if strings.Count(prompts, "Port to listen on") != 3 || strings.Count(prompts, "Choose 1-2") != 2 {
+		t.Errorf("Expected the port and level to be asked again, but got:\n%s", prompts)
+		t.Fail()
+	}
+
+	if reportContains(opt, "hunter2") {
+		t.Errorf("Expected the secret to be hidden in the report.")
+		t.Fail()
+	}
+}
+
+func TestPromptEOF(t *testing.T) {
+	opt := sopt.New()
+	opt.SetParseMode(sopt.ModePrompt)
+	opt.SetOption("", "p", "port", "Port to listen on.", nil, true, sopt.VarTypeInt, nil)
+	opt.SetIO(&sopt.IO{Stdin: strings.NewReader("x\n"), Stderr: &bytes.Buffer{}})
+	err := opt.ParseArgs([]string{})
+	if !errors.Is(err, sopt.ErrMissingRequired) {
+		t.Errorf("Expected ErrMissingRequired when the input ends, but got %v", err)
+		t.Fail()
+	}
+}
+
+func reportContains(opt *sopt.Options, s string) bool {
+	var b bytes.Buffer
+	opt.WriteReport(&b)
+	return strings.Contains(b.String(), s)
+}
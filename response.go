@@ -0,0 +1,200 @@
+package sopt
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode"
+)
+
+// maxResponseDepth is how deeply response files can include each other.
+const maxResponseDepth = 10
+
+// responseArg is an argument read from a response file, with the line it starts on.
+type responseArg struct {
+	s    string
+	line int
+}
+
+// expander replaces "@file" arguments with the arguments read from the files.
+type expander struct {
+	out []string
+	// stack of the absolute paths of the files being read.
+	stack []string
+	// stop is true after a double dash, which ends expansion like it ends option parsing.
+	stop bool
+}
+
+// expandResponseFiles returns args with each "@file" replaced by the arguments in the file, and each
+// "@@arg" replaced by "@arg". Files may include other files. Relative paths in files are relative to the
+// including file.
+func expandResponseFiles(args []string) ([]string, error) {
+	e := &expander{out: []string{}}
+	for _, arg := range args {
+		err := e.add(arg, "", 0, "")
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return e.out, nil
+}
+
+// add expands one argument read from file (or the command line, if empty) at line.
+func (e *expander) add(arg, file string, line int, dir string) error {
+	switch {
+	case e.stop || len(arg) < 2 || arg[0] != '@':
+		if arg == "--" {
+			e.stop = true
+		}
+
+		e.out = append(e.out, arg)
+	case arg[1] == '@':
+		e.out = append(e.out, arg[1:])
+	default:
+		return e.include(arg[1:], file, line, dir)
+	}
+
+	return nil
+}
+
+// include adds the arguments in the file at path, referenced from file at line.
+func (e *expander) include(path, file string, line int, dir string) error {
+	if dir != "" && !filepath.IsAbs(path) {
+		path = filepath.Join(dir, path)
+	}
+
+	// Problems with the file are reported where it's referenced.
+	fail := func(err error) error {
+		if file == "" {
+			return &ResponseFileError{File: path, Err: err}
+		}
+
+		return &ResponseFileError{File: file, Line: line, Err: fmt.Errorf("@%s: %w", path, err)}
+	}
+
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		abs = path
+	}
+
+	for _, p := range e.stack {
+		if p == abs {
+			return fail(ErrResponseCycle)
+		}
+	}
+
+	if len(e.stack) >= maxResponseDepth {
+		return fail(ErrResponseDepth)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fail(err)
+	}
+
+	list, n, err := splitResponse(string(data))
+	if err != nil {
+		return &ResponseFileError{File: path, Line: n, Err: err}
+	}
+
+	e.stack = append(e.stack, abs)
+	for _, a := range list {
+		err = e.add(a.s, path, a.line, filepath.Dir(path))
+		if err != nil {
+			return err
+		}
+	}
+
+	e.stack = e.stack[:len(e.stack)-1]
+	return nil
+}
+
+// splitResponse splits the contents of a response file into arguments like a POSIX shell does, without
+// any expansions: whitespace separates arguments, single and double quotes group them, backslashes escape
+// the next character outside single quotes, and "#" at the start of an argument comments out the rest
+// of the line. For an unterminated quote, the line it starts on is returned with the error.
+func splitResponse(data string) ([]responseArg, int, error) {
+	list := []responseArg{}
+	var b strings.Builder
+	runes := []rune(data)
+	line, start, quoteLine := 1, 0, 0
+	in := false
+	var quote rune
+	// begin starts an argument on the current line, unless already in one.
+	begin := func() {
+		if !in {
+			in, start = true, line
+		}
+	}
+
+	for i := 0; i < len(runes); i++ {
+		c := runes[i]
+		switch {
+		case quote == '\'':
+			if c == '\'' {
+				quote = 0
+			} else {
+				b.WriteRune(c)
+			}
+
+		case quote == '"':
+			switch {
+			case c == '"':
+				quote = 0
+			case c == '\\' && i+1 < len(runes) && strings.ContainsRune("\"\\$`\n", runes[i+1]):
+				i++
+				// A backslash before a line break continues the line.
+				if runes[i] != '\n' {
+					b.WriteRune(runes[i])
+				}
+			default:
+				b.WriteRune(c)
+			}
+
+		case c == '\'' || c == '"':
+			begin()
+			quote, quoteLine = c, line
+
+		case c == '\\':
+			if i+1 < len(runes) {
+				i++
+				if runes[i] != '\n' {
+					begin()
+					b.WriteRune(runes[i])
+				}
+			}
+
+		case c == '#' && !in:
+			for i+1 < len(runes) && runes[i+1] != '\n' {
+				i++
+			}
+
+		case unicode.IsSpace(c):
+			if in {
+				list = append(list, responseArg{s: b.String(), line: start})
+				b.Reset()
+				in = false
+			}
+
+		default:
+			begin()
+			b.WriteRune(c)
+		}
+
+		if runes[i] == '\n' {
+			line++
+		}
+	}
+
+	if quote != 0 {
+		return nil, quoteLine, ErrResponseSyntax
+	}
+
+	if in {
+		list = append(list, responseArg{s: b.String(), line: start})
+	}
+
+	return list, 0, nil
+}
@@ -0,0 +1,107 @@
+package sopt_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/grimdork/sopt"
+)
+
+func responseOptions() *sopt.Options {
+	opt := sopt.New()
+	opt.SetParseMode(sopt.ModeResponseFiles)
+	opt.SetOption("", "I", "include", "Include paths.", nil, false, sopt.VarTypeStringSlice, nil)
+	opt.SetOption("", "n", "name", "Name.", nil, false, sopt.VarTypeString, nil)
+	opt.SetPositional("FILES", "Files.", nil, false, sopt.VarTypePosStringSlice)
+	return opt
+}
+
+func TestResponseFiles(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "inc", "more.rsp"), "-I 'with space' # trailing comment\n")
+	path := writeFile(t, filepath.Join(dir, "args.rsp"), `# Generated flags
+-I a -I "b \"c\""
+--name=x\ y
+@inc/more.rsp
+@@literal
+`)
+	opt := responseOptions()
+	err := opt.ParseArgs([]string{"@" + path, "file", "--", "@not-a-file"})
+	if err != nil {
+		t.Errorf("Expected no error, but got %s", err.Error())
+		t.FailNow()
+	}
+
+	inc := opt.GetStringSlice("include")
+	if len(inc) != 3 || inc[0] != "a" || inc[1] != `b "c"` || inc[2] != "with space" {
+		t.Errorf("Expected three includes, but got %q", inc)
+		t.Fail()
+	}
+
+	files := opt.GetPosStringSlice("FILES")
+	if opt.GetString("name") != "x y" || len(files) != 3 || files[0] != "@literal" || files[2] != "@not-a-file" {
+		t.Errorf("Expected the name and three files, but got %q and %q", opt.GetString("name"), files)
+		t.Fail()
+	}
+}
+
+func TestResponseFileErrors(t *testing.T) {
+	dir := t.TempDir()
+	a := writeFile(t, filepath.Join(dir, "a.rsp"), "-n x\n@b.rsp\n")
+	writeFile(t, filepath.Join(dir, "b.rsp"), "\n\n@a.rsp\n")
+	err := responseOptions().ParseArgs([]string{"@" + a})
+	var re *sopt.ResponseFileError
+	if !errors.Is(err, sopt.ErrResponseCycle) || !errors.As(err, &re) || re.Line != 3 ||
+		re.File != filepath.Join(dir, "b.rsp") {
+		t.Errorf("Expected a cycle in b.rsp line 3, but got %v", err)
+		t.Fail()
+	}
+
+	bad := writeFile(t, filepath.Join(dir, "bad.rsp"), "-n x\n-I 'open\n\n")
+	err = responseOptions().ParseArgs([]string{"@" + bad})
+	if !errors.Is(err, sopt.ErrResponseSyntax) || !errors.As(err, &re) || re.Line != 2 {
+		t.Errorf("Expected an unterminated quote on line 2, but got %v", err)
+		t.Fail()
+	}
+
+	err = responseOptions().ParseArgs([]string{"@" + filepath.Join(dir, "missing.rsp")})
+	if !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Expected a missing file, but got %v", err)
+		t.Fail()
+	}
+
+	r, err := responseOptions().ParseResult(context.Background(), []string{"@" + filepath.Join(dir, "missing.rsp")})
+	if !errors.Is(err, os.ErrNotExist) || len(r.Path()) != 0 || len(r.Remainder()) != 0 {
+		t.Errorf("Expected a missing file and an empty result, but got %v", err)
+		t.Fail()
+	}
+
+	self := writeFile(t, filepath.Join(dir, "deep.rsp"), "")
+	for i := 0; i < 12; i++ {
+		next := filepath.Join(dir, "deep"+string(rune('a'+i))+".rsp")
+		writeFile(t, self, "@"+next)
+		self = next
+	}
+	writeFile(t, self, "-n x")
+	err = responseOptions().ParseArgs([]string{"@" + filepath.Join(dir, "deep.rsp")})
+	if !errors.Is(err, sopt.ErrResponseDepth) {
+		t.Errorf("Expected too deep nesting, but got %v", err)
+		t.Fail()
+	}
+}
+
+func writeFile(t *testing.T, path, data string) string {
+	err := os.MkdirAll(filepath.Dir(path), 0700)
+	if err == nil {
+		err = os.WriteFile(path, []byte(data), 0600)
+	}
+
+	if err != nil {
+		t.Fatalf("Couldn't write %s: %s", path, err.Error())
+	}
+
+	return path
+}
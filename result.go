@@ -0,0 +1,218 @@
+package sopt
+
+import (
+	"context"
+	"fmt"
+)
+
+// Result holds the values of one parse of the command line. Parsing doesn't change the definitions in
+// Options, so one Options can parse any number of command lines, also concurrently with ParseResult.
+// Custom Values and the struct fields of Bind are shared by all parses, though.
+type Result struct {
+	// Command is the innermost command selected, or nil.
+	Command *Command
+	// Args are the arguments the command is called with.
+	Args []string
+
+	// opt is the Options parsed.
+	opt *Options
+	// values of the options and positional arguments given.
+	values map[*Option]any
+	// sources of the values.
+	sources map[*Option]Source
+	// path of the Options of the selected commands, starting with opt.
+	path []*Options
+	// remainder of each level.
+	remainder map[*Options][]string
+	// errs collected in ModeCollectErrors.
+	errs []*ParseError
+	// prompter asks for missing values in ModePrompt.
+	prompter *prompter
+}
+
+// resultKey is the context key of the Result passed to commands.
+type resultKey struct{}
+
+// newResult returns an empty Result for opt.
+func newResult(opt *Options) *Result {
+	return &Result{
+		opt:       opt,
+		values:    make(map[*Option]any),
+		sources:   make(map[*Option]Source),
+		remainder: make(map[*Options][]string),
+	}
+}
+
+// ResultFrom returns the Result passed to commands in their context, or nil.
+func ResultFrom(ctx context.Context) *Result {
+	r, _ := ctx.Value(resultKey{}).(*Result)
+	return r
+}
+
+// Path returns the names of the selected commands.
+func (r *Result) Path() []string {
+	list := []string{}
+	if len(r.path) == 0 {
+		return list
+	}
+
+	for _, o := range r.path[1:] {
+		list = append(list, o.name)
+	}
+
+	return list
+}
+
+// Remainder returns the arguments not parsed as options, commands or positional arguments, at all
+// levels of the selected commands.
+func (r *Result) Remainder() []string {
+	list := []string{}
+	for _, o := range r.path {
+		list = append(list, r.remainder[o]...)
+	}
+
+	return list
+}
+
+// Value returns the value of an option or positional argument, or its default if unset. Names are
+// looked up in the innermost selected command first, then in its parents.
+func (r *Result) Value(name string) any {
+	o := r.option(name)
+	if o == nil {
+		return nil
+	}
+
+	return r.get(o)
+}
+
+// IsSet returns true if an option or positional argument got a value from the command line, the
+// environment or a configuration file.
+func (r *Result) IsSet(name string) bool {
+	o := r.option(name)
+	return o != nil && r.isSet(o)
+}
+
+// Lookup returns the value of an option or positional argument in a Result, or its default if unset.
+// It returns an error if there is no such option, or its value isn't of type T.
+func Lookup[T any](r *Result, name string) (T, error) {
+	o := r.option(name)
+	if o == nil {
+		var zero T
+		return zero, fmt.Errorf("%s: %w", name, ErrUnknownOption)
+	}
+
+	return value[T](r, o)
+}
+
+// option finds an option or positional argument by name from the innermost selected command.
+func (r *Result) option(name string) *Option {
+	leaf := r.leaf()
+	o := leaf.GetOption(name)
+	if o != nil {
+		return o
+	}
+
+	for p := leaf; p != nil; p = p.parent {
+		o = p.posmap[name]
+		if o != nil {
+			return o
+		}
+	}
+
+	return nil
+}
+
+// lookup returns the value an option got while parsing, if any.
+func (r *Result) lookup(o *Option) (any, bool) {
+	if r == nil || o == nil {
+		return nil, false
+	}
+
+	v, ok := r.values[o]
+	return v, ok
+}
+
+// get returns an option's value, or its default.
+func (r *Result) get(o *Option) any {
+	v, ok := r.lookup(o)
+	if !ok {
+		return o.Default
+	}
+
+	return v
+}
+
+// isSet returns true if the option got a value while parsing.
+func (r *Result) isSet(o *Option) bool {
+	_, ok := r.lookup(o)
+	return ok
+}
+
+// leaf returns the Options of the innermost selected command.
+func (r *Result) leaf() *Options {
+	if len(r.path) == 0 {
+		return r.opt
+	}
+
+	return r.path[len(r.path)-1]
+}
+
+// has returns true if opt is one of the selected levels.
+func (r *Result) has(opt *Options) bool {
+	for _, o := range r.path {
+		if o == opt {
+			return true
+		}
+	}
+
+	return false
+}
+
+// wantsHelp returns true if the default help option was set at any level of the selected commands.
+func (r *Result) wantsHelp() bool {
+	for _, o := range r.path {
+		if !o.hashelp {
+			continue
+		}
+
+		v, _ := value[bool](r, o.lookupShort("h"))
+		if v {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Reset forgets the values of the most recent ParseArgs, so the getters return defaults again.
+// Custom Values keep their state.
+func (opt *Options) Reset() {
+	opt.mu.Lock()
+	opt.last = nil
+	opt.mu.Unlock()
+	opt.Remainder = nil
+	for _, cmd := range opt.commands {
+		cmd.Options.Reset()
+	}
+}
+
+// setLatest makes r the result used by the getters.
+func (opt *Options) setLatest(r *Result) {
+	opt.mu.Lock()
+	opt.last = r
+	opt.mu.Unlock()
+}
+
+// latest returns the result of the most recent ParseArgs here or in the nearest parent, or nil.
+func (opt *Options) latest() *Result {
+	for p := opt; p != nil; p = p.parent {
+		p.mu.Lock()
+		r := p.last
+		p.mu.Unlock()
+		if r != nil {
+			return r
+		}
+	}
+
+	return nil
+}
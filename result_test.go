@@ -0,0 +1,106 @@
+package sopt_test
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/grimdork/sopt"
+)
+
+func TestResultReuse(t *testing.T) {
+	opt := sopt.New()
+	opt.SetOption("", "n", "name", "Name.", "none", false, sopt.VarTypeString, nil)
+	opt.SetOption("", "I", "include", "Include paths.", nil, false, sopt.VarTypeStringSlice, nil)
+	err := opt.ParseArgs([]string{"-n", "x", "-I", "a", "extra"})
+	if err != nil {
+		t.Errorf("Expected no error, but got %s", err.Error())
+		t.FailNow()
+	}
+
+	err = opt.ParseArgs([]string{"-I", "b"})
+	if err != nil {
+		t.Errorf("Expected no error, but got %s", err.Error())
+		t.FailNow()
+	}
+
+	if opt.GetString("name") != "none" || len(opt.GetStringSlice("include")) != 1 || len(opt.Remainder) != 0 {
+		t.Errorf("Expected values from the second parse only, but got %s, %v and %v", opt.GetString("name"),
+			opt.GetStringSlice("include"), opt.Remainder)
+		t.Fail()
+	}
+
+	opt.Reset()
+	if len(opt.GetStringSlice("include")) != 0 {
+		t.Errorf("Expected no values after Reset, but got %v", opt.GetStringSlice("include"))
+		t.Fail()
+	}
+}
+
+func TestParseResult(t *testing.T) {
+	opt := sopt.New()
+	opt.SetOption("", "v", "verbose", "Verbose.", false, false, sopt.VarTypeBool, nil)
+	var got *sopt.Result
+	var gotopt *sopt.Options
+	add := opt.SetCommandCtx("add", "Add.", "", func(ctx context.Context, o *sopt.Options, args []string) error {
+		got, gotopt = sopt.ResultFrom(ctx), o
+		return nil
+	}, nil)
+	add.Options.SetOption("", "n", "name", "Name.", nil, false, sopt.VarTypeString, nil)
+	add.Options.SetPositional("URL", "URL.", nil, false, sopt.VarTypeString)
+
+	args := []string{"-v", "add", "--name", "origin", "http://x", "--", "rest"}
+	r, err := opt.ParseResult(context.Background(), args)
+	if err != nil {
+		t.Errorf("Expected no error, but got %s", err.Error())
+		t.FailNow()
+	}
+
+	if got != r || gotopt != nil || r.Command != add || len(r.Path()) != 1 || r.Path()[0] != "add" {
+		t.Errorf("Expected the result for the add command, but got %+v", r)
+		t.Fail()
+	}
+
+	name, err := sopt.Lookup[string](r, "name")
+	if err != nil || name != "origin" || r.Value("URL") != "http://x" || r.Value("v") != true {
+		t.Errorf("Expected the parsed values, but got %v, %v and %v", name, r.Value("URL"), r.Value("v"))
+		t.Fail()
+	}
+
+	if len(r.Remainder()) != 1 || r.Remainder()[0] != "rest" || args[2] != "--name" || args[3] != "origin" {
+		t.Errorf("Expected the remainder and unchanged arguments, but got %v and %v", r.Remainder(), args)
+		t.Fail()
+	}
+
+	if opt.GetBool("v") || add.Options.GetString("name") != "" || r.IsSet("nonexistent") {
+		t.Errorf("Expected ParseResult to leave the getters alone.")
+		t.Fail()
+	}
+}
+
+func TestParseResultConcurrent(t *testing.T) {
+	opt := sopt.New()
+	opt.SetOption("", "n", "num", "Number.", 0, false, sopt.VarTypeInt, nil)
+	opt.SetOption("", "I", "include", "Include paths.", nil, false, sopt.VarTypeStringSlice, nil)
+	var wg sync.WaitGroup
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			r, err := opt.ParseResult(context.Background(), []string{"-n", fmt.Sprint(i), "-I", "a", "-I", "b"})
+			if err != nil {
+				t.Errorf("Expected no error, but got %s", err.Error())
+				return
+			}
+
+			n, _ := sopt.Lookup[int](r, "num")
+			list, _ := sopt.Lookup[[]string](r, "include")
+			if n != i || len(list) != 2 {
+				t.Errorf("Expected %d and two includes, but got %d and %v", i, n, list)
+			}
+		}(i)
+	}
+
+	wg.Wait()
+}
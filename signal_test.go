@@ -0,0 +1,51 @@
+//go:build !windows
+
+package sopt_test
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/grimdork/sopt"
+)
+
+func TestParseSignalFunc(t *testing.T) {
+	if os.Getenv("SOPT_SIGNAL_TEST") == "1" {
+		opt := sopt.New()
+		opt.SetIO(&sopt.IO{Args: []string{"run"}})
+		opt.SetCommand("run", "Run.", "", func([]string) error {
+			os.Stdout.WriteString("started\n")
+			time.Sleep(5 * time.Second)
+			os.Stdout.WriteString("finished\n")
+			return nil
+		}, nil)
+		opt.Parse(false)
+		return
+	}
+
+	cmd := exec.Command(os.Args[0], "-test.run=^TestParseSignalFunc$")
+	cmd.Env = append(os.Environ(), "SOPT_SIGNAL_TEST=1")
+	out, err := cmd.StdoutPipe()
+	if err != nil {
+		t.Fatalf("Couldn't get output: %s", err.Error())
+	}
+
+	err = cmd.Start()
+	if err != nil {
+		t.Fatalf("Couldn't start test binary: %s", err.Error())
+	}
+
+	buf := make([]byte, 8)
+	out.Read(buf)
+	cmd.Process.Signal(syscall.SIGINT)
+	err = cmd.Wait()
+	var ee *exec.ExitError
+	if !errors.As(err, &ee) || ee.Sys().(syscall.WaitStatus).Signal() != syscall.SIGINT {
+		t.Errorf("Expected the command to be interrupted, but got %v", err)
+		t.Fail()
+	}
+}
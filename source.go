@@ -0,0 +1,174 @@
+package sopt
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+)
+
+// SourceKind tells where a value came from.
+type SourceKind uint8
+
+// Source kinds
+const (
+	// SourceDefault is an option's default, or no value at all.
+	SourceDefault SourceKind = iota
+	// SourceCommandLine is an argument on the command line.
+	SourceCommandLine
+	// SourceEnv is an environment variable.
+	SourceEnv
+	// SourceConfig is an entry in a configuration file.
+	SourceConfig
+	// SourceProgram is a value set with Set.
+	SourceProgram
+	// SourcePrompt is an answer to a prompt. See ModePrompt.
+	SourcePrompt
+)
+
+// Source of an option's value.
+type Source struct {
+	// Kind of source.
+	Kind SourceKind
+	// Index of the argument in the parsed slice, for the command line.
+	Index int
+	// Name of the environment variable.
+	Name string
+	// File and Line of the configuration entry.
+	File string
+	Line int
+}
+
+// String describes the source.
+func (s Source) String() string {
+	switch s.Kind {
+	case SourceCommandLine:
+		return fmt.Sprintf("command line (argument %d)", s.Index)
+	case SourceEnv:
+		return "environment ($" + s.Name + ")"
+	case SourceConfig:
+		return fmt.Sprintf("%s:%d", s.File, s.Line)
+	case SourceProgram:
+		return "program"
+	case SourcePrompt:
+		return "prompt"
+	}
+
+	return "default"
+}
+
+// put stores an option's value and where it came from.
+func (r *Result) put(o *Option, v any, src Source) {
+	r.values[o] = v
+	r.sources[o] = src
+}
+
+// Source returns where the value of an option or positional argument came from.
+func (r *Result) Source(name string) Source {
+	return r.sources[r.option(name)]
+}
+
+// Source returns where the value of an option or positional argument in the most recent ParseArgs
+// came from.
+func (opt *Options) Source(name string) Source {
+	r := opt.latest()
+	if r == nil {
+		return Source{}
+	}
+
+	o := opt.GetOption(name)
+	if o == nil {
+		o = opt.posmap[name]
+	}
+
+	return r.sources[o]
+}
+
+// Set sets the value of an option or positional argument, replacing any parsed value. Its source is
+// SourceProgram.
+func (r *Result) Set(name, s string) error {
+	return r.replace(r.option(name), name, s)
+}
+
+// Set sets the value of an option or positional argument in the most recent ParseArgs, as Result.Set.
+// Before any parse, the value lasts until the first one.
+func (opt *Options) Set(name, s string) error {
+	r := opt.latest()
+	if r == nil {
+		r = newResult(opt)
+		opt.setLatest(r)
+	}
+
+	o := opt.GetOption(name)
+	if o == nil {
+		o = opt.posmap[name]
+	}
+
+	return r.replace(o, name, s)
+}
+
+// replace sets an option to s from the program, keeping the previous value if s is invalid.
+func (r *Result) replace(o *Option, name, s string) error {
+	if o == nil {
+		return fmt.Errorf("%s: %w", name, ErrUnknownOption)
+	}
+
+	v, ok := r.values[o]
+	src := r.sources[o]
+	delete(r.values, o)
+	err := r.set(o, s, Source{Kind: SourceProgram})
+	if err != nil && ok {
+		r.put(o, v, src)
+	}
+
+	return err
+}
+
+// WriteReport writes the options and positional arguments which didn't get their default, with their
+// values and sources. Options of commands are prefixed with the command path.
+func (r *Result) WriteReport(w io.Writer) error {
+	tw := &tabwriter.Writer{}
+	tw.Init(w, 8, 8, 2, ' ', 0)
+	levels := r.path
+	if len(levels) == 0 {
+		levels = []*Options{r.opt}
+	}
+
+	for _, opt := range levels {
+		prefix := opt.commandPath()
+		if prefix != "" {
+			prefix += " "
+		}
+
+		list := []*Option{}
+		for _, g := range opt.GetGroups() {
+			list = append(list, g.options...)
+		}
+		list = append(list, opt.positional...)
+
+		for _, o := range list {
+			v, ok := r.values[o]
+			if !ok {
+				continue
+			}
+
+			s := formatValue(v)
+			if o.Secret {
+				s = "(secret)"
+			}
+
+			fmt.Fprintf(tw, "%s%s\t%s\t%s\n", prefix, o.name(), s, r.sources[o])
+		}
+	}
+
+	return tw.Flush()
+}
+
+// WriteReport writes the report of the most recent ParseArgs. See Result.WriteReport.
+func (opt *Options) WriteReport(w io.Writer) error {
+	r := opt.latest()
+	if r == nil {
+		return nil
+	}
+
+	return r.WriteReport(w)
+}
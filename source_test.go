@@ -0,0 +1,76 @@
+package sopt_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/grimdork/sopt"
+)
+
+func TestSource(t *testing.T) {
+	path := writeConfig(t, "config", "verbose = yes\n\n[Network]\nport = 9000\n")
+	t.Setenv("SRCTEST_HOST", "example.com")
+	opt := configOptions()
+	opt.SetEnvPrefix("SRCTEST_")
+	remote := opt.SetCommand("remote", "Manage remotes.", "", func([]string) error { return nil }, nil)
+	remote.Options.SetPositional("URL", "Remote URL.", nil, false, sopt.VarTypeString)
+	err := opt.LoadConfig(path)
+	if err != nil {
+		t.Errorf("Expected no error, but got %s", err.Error())
+		t.FailNow()
+	}
+
+	err = opt.ParseArgs([]string{"-t", "a", "remote", "http://x"})
+	if err != nil {
+		t.Errorf("Expected no error, but got %s", err.Error())
+		t.FailNow()
+	}
+
+	tests := []struct {
+		name string
+		want sopt.Source
+	}{
+		{"tag", sopt.Source{Kind: sopt.SourceCommandLine, Index: 0}},
+		{"host", sopt.Source{Kind: sopt.SourceEnv, Name: "SRCTEST_HOST"}},
+		{"port", sopt.Source{Kind: sopt.SourceConfig, File: path, Line: 4}},
+		{"verbose", sopt.Source{Kind: sopt.SourceConfig, File: path, Line: 1}},
+	}
+
+	for _, tc := range tests {
+		got := opt.Source(tc.name)
+		if got != tc.want {
+			t.Errorf("%s: expected %s, but got %s", tc.name, tc.want, got)
+			t.Fail()
+		}
+	}
+
+	if remote.Options.Source("URL").Index != 3 {
+		t.Errorf("Expected URL from argument 3, but got %s", remote.Options.Source("URL"))
+		t.Fail()
+	}
+
+	err = opt.Set("port", "8080")
+	if err != nil || opt.GetInt("port") != 8080 || opt.Source("port").Kind != sopt.SourceProgram {
+		t.Errorf("Expected port to be set by the program, but got %d from %s", opt.GetInt("port"), opt.Source("port"))
+		t.Fail()
+	}
+
+	err = opt.Set("port", "x")
+	if !errors.Is(err, sopt.ErrInvalidValue) || opt.GetInt("port") != 8080 {
+		t.Errorf("Expected an invalid value to be rejected, but got %v", err)
+		t.Fail()
+	}
+
+	var b bytes.Buffer
+	opt.WriteReport(&b)
+	want := "--verbose   true         " + path + ":1\n" +
+		"--tag       [a]          command line (argument 0)\n" +
+		"--port      8080         program\n" +
+		"--host      example.com  environment ($SRCTEST_HOST)\n" +
+		"remote URL  http://x     command line (argument 3)\n"
+	if b.String() != want {
+		t.Errorf("Unexpected report:\n%s\nExpected:\n%s", b.String(), want)
+		t.Fail()
+	}
+}
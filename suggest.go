@@ -0,0 +1,125 @@
+package sopt
+
+import (
+	"sort"
+	"strings"
+)
+
+// unknownError returns a ParseError for an unknown option or command, with suggestions.
+func (opt *Options) unknownError(index int, arg, name string, err error) *ParseError {
+	pe := newParseError(index, arg, name, err)
+	pe.Suggestions = opt.suggest(name)
+	return pe
+}
+
+// suggest returns the long options, short options, commands and aliases similar to name, closest first.
+func (opt *Options) suggest(name string) []string {
+	candidates := []string{}
+	for p := opt; p != nil; p = p.parent {
+		for _, g := range p.GetGroups() {
+			for _, o := range g.options {
+				if o.LongName != "" {
+					candidates = append(candidates, "--"+o.LongName)
+				}
+
+				if o.ShortName != "" {
+					candidates = append(candidates, "-"+o.ShortName)
+				}
+			}
+		}
+	}
+
+	for _, cmd := range opt.commands {
+		candidates = append(candidates, cmd.Name)
+		candidates = append(candidates, cmd.Aliases...)
+	}
+
+	type match struct {
+		name string
+		dist int
+	}
+
+	matches := []match{}
+	seen := map[string]bool{}
+	lower := strings.ToLower(name)
+	max := len(strings.TrimLeft(name, "-")) / 3
+	if max < 1 {
+		max = 1
+	}
+
+	if max > 3 {
+		max = 3
+	}
+
+	for _, c := range candidates {
+		if seen[c] || c == name {
+			continue
+		}
+
+		seen[c] = true
+		d := editDistance(lower, strings.ToLower(c))
+		// Single characters are only suggested for a different case.
+		if d == 0 || d <= max && len(strings.TrimLeft(c, "-")) > 1 {
+			matches = append(matches, match{c, d})
+		}
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].dist != matches[j].dist {
+			return matches[i].dist < matches[j].dist
+		}
+
+		return matches[i].name < matches[j].name
+	})
+
+	list := []string{}
+	for _, m := range matches {
+		list = append(list, m.name)
+	}
+
+	return list
+}
+
+// editDistance returns the number of insertions, deletions, substitutions and transpositions of
+// adjacent characters needed to turn a into b (optimal string alignment distance).
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	d := make([][]int, len(ra)+1)
+	for i := range d {
+		d[i] = make([]int, len(rb)+1)
+		d[i][0] = i
+	}
+
+	for j := range d[0] {
+		d[0][j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+
+			d[i][j] = min3(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] && d[i-2][j-2]+1 < d[i][j] {
+				d[i][j] = d[i-2][j-2] + 1
+			}
+		}
+	}
+
+	return d[len(ra)][len(rb)]
+}
+
+// min3 returns the smallest of three ints.
+func min3(a, b, c int) int {
+	if b < a {
+		a = b
+	}
+
+	if c < a {
+		a = c
+	}
+
+	return a
+}
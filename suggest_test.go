@@ -0,0 +1,80 @@
+package sopt_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/grimdork/sopt"
+)
+
+func TestParseErrorSuggestions(t *testing.T) {
+	opt := sopt.New()
+	opt.SetOption("", "v", "verbose", "Show more details in output.", false, false, sopt.VarTypeBool, nil)
+	opt.SetOption("", "", "version", "Show the version.", false, false, sopt.VarTypeBool, nil)
+	opt.SetOption("", "p", "port", "Port to listen on.", 8080, false, sopt.VarTypeInt, nil)
+	err := opt.ParseArgs([]string{"-p", "80", "--verbos"})
+	var pe *sopt.ParseError
+	if !errors.As(err, &pe) || !errors.Is(err, sopt.ErrUnknownOption) {
+		t.Errorf("Expected a ParseError for an unknown option, but got %v", err)
+		t.FailNow()
+	}
+
+	if pe.Index != 2 || pe.Arg != "--verbos" || pe.Option != "--verbos" {
+		t.Errorf("Expected index 2 and --verbos, but got %d, %s and %s", pe.Index, pe.Arg, pe.Option)
+		t.Fail()
+	}
+
+	if len(pe.Suggestions) == 0 || pe.Suggestions[0] != "--verbose" {
+		t.Errorf("Expected --verbose as the first suggestion, but got %v", pe.Suggestions)
+		t.Fail()
+	}
+
+	if !strings.HasPrefix(err.Error(), "--verbos: unknown option\n\nDid you mean") {
+		t.Errorf("Unexpected message: %q", err.Error())
+		t.Fail()
+	}
+
+	err = opt.ParseArgs([]string{"--port", "eighty"})
+	if !errors.As(err, &pe) || !errors.Is(err, sopt.ErrInvalidValue) || pe.Cause == nil || pe.Index != 0 {
+		t.Errorf("Expected a ParseError for an invalid value, but got %v", err)
+		t.Fail()
+	}
+
+	err = opt.ParseArgs([]string{"--port"})
+	if !errors.Is(err, sopt.ErrMissingArgument) || err.Error() != "--port: missing argument" {
+		t.Errorf("Expected a missing argument error, but got %v", err)
+		t.Fail()
+	}
+}
+
+func TestParseErrorCommand(t *testing.T) {
+	opt := sopt.New()
+	remote := opt.SetCommand("remote", "Manage remotes.", "", nil, nil)
+	remote.Options.SetCommand("add", "Add a remote.", "", func(args []string) error { return nil }, nil)
+	remote.Options.SetOption("", "n", "name", "Remote name.", nil, false, sopt.VarTypeString, nil)
+	err := opt.ParseArgs([]string{"remot", "add"})
+	if err != nil || len(opt.Remainder) != 2 {
+		t.Errorf("Expected the arguments in Remainder, but got %v and %v", err, opt.Remainder)
+		t.Fail()
+	}
+
+	opt.SetParseMode(sopt.ModeStrictCommands)
+	err = opt.ParseArgs([]string{"remot", "add"})
+	var pe *sopt.ParseError
+	if !errors.As(err, &pe) || !errors.Is(err, sopt.ErrUnknownCommand) {
+		t.Errorf("Expected a ParseError for an unknown command, but got %v", err)
+		t.FailNow()
+	}
+
+	if len(pe.Suggestions) != 1 || pe.Suggestions[0] != "remote" {
+		t.Errorf("Expected remote as the suggestion, but got %v", pe.Suggestions)
+		t.Fail()
+	}
+
+	err = opt.ParseArgs([]string{"remote", "--nmae", "x", "add"})
+	if !errors.As(err, &pe) || pe.Index != 1 || len(pe.Suggestions) == 0 || pe.Suggestions[0] != "--name" {
+		t.Errorf("Expected index 1 with --name suggested, but got %v", err)
+		t.Fail()
+	}
+}
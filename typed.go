@@ -0,0 +1,143 @@
+package sopt
+
+import (
+	"fmt"
+	"net/url"
+	"time"
+)
+
+// Var is a typed handle to an option, returned by Add.
+type Var[T any] struct {
+	opt *Options
+	o   *Option
+}
+
+// Get returns the option's value from the most recent ParseArgs, or its default if unset.
+func (v *Var[T]) Get() T {
+	t, _ := value[T](v.opt.latest(), v.o)
+	return t
+}
+
+// From returns the option's value in r, or its default if unset.
+func (v *Var[T]) From(r *Result) T {
+	t, _ := value[T](r, v.o)
+	return t
+}
+
+// Option returns the option definition.
+func (v *Var[T]) Option() *Option {
+	return v.o
+}
+
+// Add registers an option whose type is derived from T: bool, int, float64, string, []string,
+// time.Duration, Size, time.Time or *url.URL.
+// It returns an error if T has no matching option type.
+func Add[T any](opt *Options, group, short, long, help string, defaultvalue T, required bool, choices []T) (*Var[T], error) {
+	t, ok := typeOf(any(defaultvalue))
+	if !ok {
+		return nil, fmt.Errorf("%T: %w", defaultvalue, ErrUnknownType)
+	}
+
+	var list []any
+	for _, c := range choices {
+		list = append(list, c)
+	}
+
+	err := opt.SetOption(group, short, long, help, nilDefault(defaultvalue), required, t, list)
+	if err != nil {
+		return nil, err
+	}
+
+	o := opt.long[long]
+	if o == nil {
+		o = opt.short[short]
+	}
+
+	return &Var[T]{opt: opt, o: o}, nil
+}
+
+// Get returns the value of an option or positional argument, or its default if unset.
+// It returns an error if there is no such option, or its value isn't of type T.
+func Get[T any](opt *Options, name string) (T, error) {
+	o := opt.GetOption(name)
+	if o == nil {
+		o = opt.posmap[name]
+	}
+
+	if o == nil {
+		var zero T
+		return zero, fmt.Errorf("%s: %w", name, ErrUnknownOption)
+	}
+
+	return value[T](opt.latest(), o)
+}
+
+// value returns an option's value in r, or its default, as T. A nil r gives the default.
+func value[T any](r *Result, o *Option) (T, error) {
+	var zero T
+	v := r.get(o)
+
+	if v == nil {
+		return zero, nil
+	}
+
+	t, ok := v.(T)
+	if !ok {
+		return zero, fmt.Errorf("%s: %w: %T is not %T", o.name(), ErrWrongType, v, zero)
+	}
+
+	return t, nil
+}
+
+// typeOf returns the option type matching a Go value's type.
+func typeOf(v any) (uint8, bool) {
+	switch v.(type) {
+	case bool:
+		return VarTypeBool, true
+	case int:
+		return VarTypeInt, true
+	case float64:
+		return VarTypeFloat, true
+	case string:
+		return VarTypeString, true
+	case []string:
+		return VarTypeStringSlice, true
+	case time.Duration:
+		return VarTypeDuration, true
+	case Size:
+		return VarTypeSize, true
+	case time.Time:
+		return VarTypeTime, true
+	case *url.URL:
+		return VarTypeURL, true
+	}
+
+	return 0, false
+}
+
+// checkType returns an error if v isn't nil and doesn't have the Go type of option type t.
+func checkType(name string, t uint8, v any) error {
+	if v == nil {
+		return nil
+	}
+
+	vt, ok := typeOf(v)
+	if t == VarTypePosStringSlice {
+		t = VarTypeStringSlice
+	}
+
+	// Custom values show their initial string form as the default.
+	if t == VarTypeValue {
+		t = VarTypeString
+	}
+
+	if t == VarTypeCount {
+		t = VarTypeInt
+	}
+
+	if !ok || vt != t {
+		return fmt.Errorf("%s: %w: default %T doesn't match the option type", name, ErrWrongType, v)
+	}
+
+	return nil
+}
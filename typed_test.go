@@ -0,0 +1,67 @@
+package sopt_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/grimdork/sopt"
+)
+
+func TestAddGet(t *testing.T) {
+	opt := sopt.New()
+	port, err := sopt.Add(opt, "", "p", "port", "Port number.", 3000, false, nil)
+	if err != nil {
+		t.Errorf("Expected no error, but got %s", err.Error())
+		t.FailNow()
+	}
+
+	format, err := sopt.Add(opt, "", "f", "format", "Output format.", "json", false, []string{"json", "yaml"})
+	if err != nil {
+		t.Errorf("Expected no error, but got %s", err.Error())
+		t.FailNow()
+	}
+
+	err = opt.ParseArgs([]string{"-p", "4000"})
+	if err != nil {
+		t.Errorf("Expected no error, but got %s", err.Error())
+		t.FailNow()
+	}
+
+	if port.Get() != 4000 || format.Get() != "json" {
+		t.Errorf("Expected 4000 and json, but got %d and %s", port.Get(), format.Get())
+		t.Fail()
+	}
+
+	p, err := sopt.Get[int](opt, "port")
+	if err != nil || p != 4000 {
+		t.Errorf("Expected 4000, but got %d (%v)", p, err)
+		t.Fail()
+	}
+
+	_, err = sopt.Get[float64](opt, "port")
+	if !errors.Is(err, sopt.ErrWrongType) {
+		t.Errorf("Expected ErrWrongType, but got %v", err)
+		t.Fail()
+	}
+
+	_, err = sopt.Get[int](opt, "nope")
+	if !errors.Is(err, sopt.ErrUnknownOption) {
+		t.Errorf("Expected ErrUnknownOption, but got %v", err)
+		t.Fail()
+	}
+}
+
+func TestWrongDefault(t *testing.T) {
+	opt := sopt.New()
+	err := opt.SetOption("", "p", "pi", "Your definition of pi.", 3, false, sopt.VarTypeFloat, nil)
+	if !errors.Is(err, sopt.ErrWrongType) {
+		t.Errorf("Expected ErrWrongType, but got %v", err)
+		t.Fail()
+	}
+
+	_, err = sopt.Add(opt, "", "r", "ratio", "Ratio.", float32(0.5), false, nil)
+	if !errors.Is(err, sopt.ErrUnknownType) {
+		t.Errorf("Expected ErrUnknownType, but got %v", err)
+		t.Fail()
+	}
+}
@@ -0,0 +1,261 @@
+package sopt
+
+import (
+	"fmt"
+	"math"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Size in bytes, parsed from numbers with an optional unit: B, KB, MB, GB, TB and PB are powers of 1000,
+// while K, M, G, T and P, with or without "iB", are powers of 1024. Units are case-insensitive.
+type Size int64
+
+// sizeUnits in the order they are tried when formatting.
+var sizeUnits = []struct {
+	name string
+	mult int64
+}{
+	{"PiB", 1 << 50},
+	{"TiB", 1 << 40},
+	{"GiB", 1 << 30},
+	{"MiB", 1 << 20},
+	{"KiB", 1 << 10},
+	{"PB", 1e15},
+	{"TB", 1e12},
+	{"GB", 1e9},
+	{"MB", 1e6},
+	{"KB", 1e3},
+}
+
+// String returns the size with the largest unit dividing it evenly, e.g. "512MiB".
+func (s Size) String() string {
+	if s != 0 {
+		for _, u := range sizeUnits {
+			if int64(s)%u.mult == 0 {
+				return strconv.FormatInt(int64(s)/u.mult, 10) + u.name
+			}
+		}
+	}
+
+	return strconv.FormatInt(int64(s), 10) + "B"
+}
+
+// ParseSize parses a size such as "512MiB", "1.5G" or "100kb".
+func ParseSize(s string) (Size, error) {
+	s = strings.TrimSpace(s)
+	i := strings.IndexFunc(s, func(r rune) bool {
+		return (r < '0' || r > '9') && r != '.'
+	})
+
+	num, unit := s, ""
+	if i >= 0 {
+		num, unit = s[:i], strings.ToLower(strings.TrimSpace(s[i:]))
+	}
+
+	f, err := strconv.ParseFloat(num, 64)
+	if err != nil || num == "" {
+		return 0, fmt.Errorf("%w %q", ErrInvalidValue, s)
+	}
+
+	var mult float64
+	switch unit {
+	case "", "b":
+		mult = 1
+	case "k", "kib":
+		mult = 1 << 10
+	case "m", "mib":
+		mult = 1 << 20
+	case "g", "gib":
+		mult = 1 << 30
+	case "t", "tib":
+		mult = 1 << 40
+	case "p", "pib":
+		mult = 1 << 50
+	case "kb":
+		mult = 1e3
+	case "mb":
+		mult = 1e6
+	case "gb":
+		mult = 1e9
+	case "tb":
+		mult = 1e12
+	case "pb":
+		mult = 1e15
+	default:
+		return 0, fmt.Errorf("%w %q", ErrInvalidValue, s)
+	}
+
+	// float64(math.MaxInt64) rounds up to 2^63, which doesn't fit either.
+	if f*mult >= math.MaxInt64 {
+		return 0, fmt.Errorf("%w %q: too large", ErrInvalidValue, s)
+	}
+
+	return Size(f * mult), nil
+}
+
+// parseTime parses RFC 3339 timestamps, or dates in the form 2006-01-02.
+func parseTime(s string) (time.Time, error) {
+	t, err := time.Parse(time.RFC3339, s)
+	if err == nil {
+		return t, nil
+	}
+
+	return time.Parse("2006-01-02", s)
+}
+
+// parseURL parses absolute URLs.
+func parseURL(s string) (*url.URL, error) {
+	u, err := url.Parse(s)
+	if err != nil {
+		return nil, err
+	}
+
+	if !u.IsAbs() {
+		return nil, fmt.Errorf("%w %q", ErrInvalidValue, s)
+	}
+
+	return u, nil
+}
+
+// typeFormat describes the expected format of a value type in error messages.
+func typeFormat(t uint8) string {
+	switch t {
+	case VarTypeInt, VarTypeCount:
+		return "an integer"
+	case VarTypeFloat:
+		return "a number"
+	case VarTypeDuration:
+		return "a duration like 30s or 1h15m"
+	case VarTypeSize:
+		return "a size like 512MiB or 10GB"
+	case VarTypeTime:
+		return "a time like 2006-01-02T15:04:05Z07:00 or 2006-01-02"
+	case VarTypeURL:
+		return "an absolute URL like https://example.com/"
+	}
+
+	return "a value"
+}
+
+// formatValue returns a value as shown in help text.
+func formatValue(v any) string {
+	switch x := v.(type) {
+	case time.Time:
+		return x.Format(time.RFC3339)
+	case *url.URL:
+		if x == nil {
+			return ""
+		}
+
+		return x.String()
+	}
+
+	return fmt.Sprint(v)
+}
+
+// nilDefault returns nil for a nil URL or slice, so it counts as no default.
+func nilDefault(v any) any {
+	switch x := v.(type) {
+	case *url.URL:
+		if x == nil {
+			return nil
+		}
+	case []string:
+		if x == nil {
+			return nil
+		}
+	}
+
+	return v
+}
+
+// GetDuration returns a duration option's value.
+func (opt *Options) GetDuration(name string) time.Duration {
+	o := opt.GetOption(name)
+	if o == nil {
+		return 0
+	}
+
+	v, _ := value[time.Duration](opt.latest(), o)
+	return v
+}
+
+// GetSize returns a size option's value in bytes.
+func (opt *Options) GetSize(name string) Size {
+	o := opt.GetOption(name)
+	if o == nil {
+		return 0
+	}
+
+	v, _ := value[Size](opt.latest(), o)
+	return v
+}
+
+// GetTime returns a time option's value.
+func (opt *Options) GetTime(name string) time.Time {
+	o := opt.GetOption(name)
+	if o == nil {
+		return time.Time{}
+	}
+
+	v, _ := value[time.Time](opt.latest(), o)
+	return v
+}
+
+// GetURL returns a URL option's value.
+func (opt *Options) GetURL(name string) *url.URL {
+	o := opt.GetOption(name)
+	if o == nil {
+		return nil
+	}
+
+	v, _ := value[*url.URL](opt.latest(), o)
+	return v
+}
+
+// GetPosDuration returns a positional duration's value.
+func (opt *Options) GetPosDuration(placeholder string) time.Duration {
+	o := opt.posmap[placeholder]
+	if o == nil {
+		return 0
+	}
+
+	v, _ := value[time.Duration](opt.latest(), o)
+	return v
+}
+
+// GetPosSize returns a positional size's value in bytes.
+func (opt *Options) GetPosSize(placeholder string) Size {
+	o := opt.posmap[placeholder]
+	if o == nil {
+		return 0
+	}
+
+	v, _ := value[Size](opt.latest(), o)
+	return v
+}
+
+// GetPosTime returns a positional time's value.
+func (opt *Options) GetPosTime(placeholder string) time.Time {
+	o := opt.posmap[placeholder]
+	if o == nil {
+		return time.Time{}
+	}
+
+	v, _ := value[time.Time](opt.latest(), o)
+	return v
+}
+
+// GetPosURL returns a positional URL's value.
+func (opt *Options) GetPosURL(placeholder string) *url.URL {
+	o := opt.posmap[placeholder]
+	if o == nil {
+		return nil
+	}
+
+	v, _ := value[*url.URL](opt.latest(), o)
+	return v
+}
@@ -0,0 +1,142 @@
+package sopt_test
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/grimdork/sopt"
+)
+
+func TestParseSize(t *testing.T) {
+	tests := []struct {
+		s    string
+		want sopt.Size
+	}{
+		{"512", 512},
+		{"512B", 512},
+		{"512MiB", 512 << 20},
+		{"1.5G", 3 << 29},
+		{"100kb", 100000},
+		{"2 TB", 2e12},
+	}
+
+	for _, tt := range tests {
+		got, err := sopt.ParseSize(tt.s)
+		if err != nil || got != tt.want {
+			t.Errorf("ParseSize(%q): expected %d, but got %d (%v)", tt.s, tt.want, got, err)
+			t.Fail()
+		}
+	}
+
+	_, err := sopt.ParseSize("12 parsecs")
+	if !errors.Is(err, sopt.ErrInvalidValue) {
+		t.Errorf("Expected ErrInvalidValue, but got %v", err)
+		t.Fail()
+	}
+
+	_, err = sopt.ParseSize("99999999PiB")
+	if !errors.Is(err, sopt.ErrInvalidValue) {
+		t.Errorf("Expected ErrInvalidValue for an overflowing size, but got %v", err)
+		t.Fail()
+	}
+
+	got, err := sopt.ParseSize("8191PiB")
+	if err != nil || got != 8191<<50 {
+		t.Errorf("Expected 8191PiB, but got %s (%v)", got, err)
+		t.Fail()
+	}
+
+	if sopt.Size(512<<20).String() != "512MiB" || sopt.Size(1500).String() != "1500B" {
+		t.Errorf("Expected human-readable sizes, but got %s and %s", sopt.Size(512<<20), sopt.Size(1500))
+		t.Fail()
+	}
+}
+
+func TestBuiltinTypes(t *testing.T) {
+	opt := sopt.New()
+	opt.SetOption("", "t", "timeout", "Timeout.", 30*time.Second, false, sopt.VarTypeDuration, nil)
+	opt.SetOption("", "m", "max-size", "Maximum size.", sopt.Size(1<<30), false, sopt.VarTypeSize, nil)
+	opt.SetOption("", "s", "since", "Start time.", nil, false, sopt.VarTypeTime, nil)
+	opt.SetOption("", "e", "endpoint", "Endpoint.", nil, false, sopt.VarTypeURL, nil)
+	opt.SetPositional("WAIT", "Time to wait.", nil, false, sopt.VarTypeDuration)
+	var buf strings.Builder
+	opt.WriteHelp(&buf)
+	t.Log(buf.String())
+	if !strings.Contains(buf.String(), "(default: 30s)") || !strings.Contains(buf.String(), "(default: 1GiB)") {
+		t.Errorf("Expected human-readable defaults in help text.")
+		t.Fail()
+	}
+
+	args := []string{"--max-size", "512MiB", "--since=2026-01-01T00:00:00Z", "-e", "https://example.com/api", "1m"}
+	err := opt.ParseArgs(args)
+	if err != nil {
+		t.Errorf("Expected no error, but got %s", err.Error())
+		t.FailNow()
+	}
+
+	if opt.GetDuration("timeout") != 30*time.Second || opt.GetSize("max-size") != 512<<20 {
+		t.Errorf("Expected 30s and 512MiB, but got %s and %s", opt.GetDuration("timeout"), opt.GetSize("max-size"))
+		t.Fail()
+	}
+
+	if opt.GetTime("since").Year() != 2026 || opt.GetURL("endpoint").Host != "example.com" {
+		t.Errorf("Expected 2026 and example.com, but got %s and %s", opt.GetTime("since"), opt.GetURL("endpoint"))
+		t.Fail()
+	}
+
+	wait, err := sopt.Get[time.Duration](opt, "WAIT")
+	if err != nil || wait != time.Minute {
+		t.Errorf("Expected 1m, but got %s (%v)", wait, err)
+		t.Fail()
+	}
+
+	if opt.GetPosDuration("WAIT") != time.Minute {
+		t.Errorf("Expected 1m from the positional getter, but got %s", opt.GetPosDuration("WAIT"))
+		t.Fail()
+	}
+
+	err = opt.ParseArgs([]string{"--timeout", "soon"})
+	var ve *sopt.ValueError
+	if !errors.As(err, &ve) || ve.Option != "--timeout" || !strings.Contains(err.Error(), "duration") {
+		t.Errorf("Expected error naming option and format, but got %v", err)
+		t.Fail()
+	} else {
+		t.Logf("Bad duration failed as expected: %s", err.Error())
+	}
+
+	err = opt.ParseArgs([]string{"--endpoint", "example.com"})
+	if !errors.Is(err, sopt.ErrInvalidValue) {
+		t.Errorf("Expected ErrInvalidValue, but got %v", err)
+		t.Fail()
+	}
+}
+
+func TestNilURLDefault(t *testing.T) {
+	opt := sopt.New()
+	_, err := sopt.Add[*url.URL](opt, "", "e", "endpoint", "Endpoint.", nil, false, nil)
+	if err != nil {
+		t.Errorf("Expected no error, but got %s", err.Error())
+		t.FailNow()
+	}
+
+	var buf strings.Builder
+	opt.WriteHelp(&buf)
+	if strings.Contains(buf.String(), "(default:") {
+		t.Errorf("Expected no default in help text, but got:\n%s", buf.String())
+		t.Fail()
+	}
+
+	err = opt.ParseArgs([]string{"-e", "file:///tmp/x"})
+	if err != nil {
+		t.Errorf("Expected no error, but got %s", err.Error())
+		t.FailNow()
+	}
+
+	if opt.GetURL("endpoint").Path != "/tmp/x" {
+		t.Errorf("Expected /tmp/x, but got %s", opt.GetURL("endpoint"))
+		t.Fail()
+	}
+}
@@ -0,0 +1,101 @@
+package sopt
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Value is implemented by custom option types. It has the same methods as the standard library's
+// flag.Value, so implementations of that work as is.
+type Value interface {
+	String() string
+	Set(string) error
+}
+
+// TypedValue is optionally implemented by a Value to name its type. The name in upper case is the
+// default placeholder in help text.
+type TypedValue interface {
+	Value
+	Type() string
+}
+
+// boolFlag is optionally implemented by a Value which doesn't need an argument, like in the flag package.
+type boolFlag interface {
+	IsBoolFlag() bool
+}
+
+// SetValue sets an option parsed by a custom Value. The current string form of v is shown as the default
+// in help text, and choices are compared with the argument before it's passed to v.Set. The string
+// getters return the string form of v, set or not.
+func (opt *Options) SetValue(group, short, long, help string, v Value, required bool, choices []any) error {
+	err := opt.SetOption(group, short, long, help, defaultString(v), required, VarTypeValue, choices)
+	if err != nil {
+		return err
+	}
+
+	o := opt.long[long]
+	if o == nil {
+		o = opt.short[short]
+	}
+
+	o.Var = v
+	o.Placeholder = typePlaceholder(v)
+	return nil
+}
+
+// SetPosValue sets a positional argument parsed by a custom Value.
+func (opt *Options) SetPosValue(placeholder, help string, v Value, required bool) error {
+	err := opt.SetPositional(placeholder, help, defaultString(v), required, VarTypeValue)
+	if err != nil {
+		return err
+	}
+
+	opt.posmap[placeholder].Var = v
+	return nil
+}
+
+// defaultString returns the string form of a Value, or nil if it's empty.
+func defaultString(v Value) any {
+	s := v.String()
+	if s == "" {
+		return nil
+	}
+
+	return s
+}
+
+// typePlaceholder returns the upper case type name of a TypedValue, or an empty string.
+func typePlaceholder(v Value) string {
+	tv, ok := v.(TypedValue)
+	if !ok {
+		return ""
+	}
+
+	return strings.ToUpper(tv.Type())
+}
+
+// setVar checks s against the choices and passes it to the option's Value.
+func (r *Result) setVar(o *Option, s string, src Source) error {
+	err := o.checkChoice(s)
+	if err != nil {
+		return err
+	}
+
+	err = o.Var.Set(s)
+	if err != nil {
+		return fmt.Errorf("%s: %w", o.name(), err)
+	}
+
+	r.put(o, o.Var.String(), src)
+	return nil
+}
+
+// boolValue returns true if the option is a Value which doesn't need an argument.
+func (o *Option) boolValue() bool {
+	if o.Type != VarTypeValue {
+		return false
+	}
+
+	bf, ok := o.Var.(boolFlag)
+	return ok && bf.IsBoolFlag()
+}
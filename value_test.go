@@ -0,0 +1,155 @@
+package sopt_test
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/grimdork/sopt"
+)
+
+type ipValue struct {
+	ip net.IP
+}
+
+func (v *ipValue) String() string {
+	if v.ip == nil {
+		return ""
+	}
+
+	return v.ip.String()
+}
+
+func (v *ipValue) Set(s string) error {
+	ip := net.ParseIP(s)
+	if ip == nil {
+		return fmt.Errorf("invalid IP address %q", s)
+	}
+
+	v.ip = ip
+	return nil
+}
+
+func (v *ipValue) Type() string {
+	return "ip"
+}
+
+type level string
+
+func (l *level) String() string     { return string(*l) }
+func (l *level) Set(s string) error { *l = level(s); return nil }
+
+func TestValue(t *testing.T) {
+	opt := sopt.New()
+	addr := &ipValue{ip: net.ParseIP("127.0.0.1")}
+	err := opt.SetValue("", "a", "addr", "Listen address.", addr, false, nil)
+	if err != nil {
+		t.Errorf("Expected no error, but got %s", err.Error())
+		t.FailNow()
+	}
+
+	lvl := level("info")
+	err = opt.SetValue("", "l", "level", "Log level.", &lvl, false, []any{"debug", "info", "warn"})
+	if err != nil {
+		t.Errorf("Expected no error, but got %s", err.Error())
+		t.FailNow()
+	}
+
+	var buf strings.Builder
+	opt.WriteHelp(&buf)
+	t.Log(buf.String())
+	if !strings.Contains(buf.String(), "--addr IP") || !strings.Contains(buf.String(), "(default: info)") {
+		t.Errorf("Expected placeholder and default in help text.")
+		t.Fail()
+	}
+
+	err = opt.ParseArgs([]string{"--addr", "10.0.0.1", "-l", "debug"})
+	if err != nil {
+		t.Errorf("Expected no error, but got %s", err.Error())
+		t.FailNow()
+	}
+
+	if addr.String() != "10.0.0.1" || lvl != "debug" {
+		t.Errorf("Expected 10.0.0.1 and debug, but got %s and %s", addr, lvl)
+		t.Fail()
+	}
+
+	err = opt.ParseArgs([]string{"--level=trace"})
+	if !errors.Is(err, sopt.ErrInvalidChoice) {
+		t.Errorf("Expected invalid choice, but got %v", err)
+		t.Fail()
+	}
+
+	err = opt.ParseArgs([]string{"--addr=nope"})
+	if err == nil {
+		t.Errorf("Expected error, but invalid address worked.")
+		t.Fail()
+	}
+}
+
+func TestFlagValue(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	n := fs.Int("n", 1, "")
+	verbose := fs.Bool("v", false, "")
+	opt := sopt.New()
+	opt.SetValue("", "n", "", "Count.", fs.Lookup("n").Value, false, nil)
+	opt.SetValue("", "v", "", "Verbose.", fs.Lookup("v").Value, false, nil)
+	err := opt.ParseArgs([]string{"-v", "-n", "5"})
+	if err != nil {
+		t.Errorf("Expected no error, but got %s", err.Error())
+		t.FailNow()
+	}
+
+	if *n != 5 || !*verbose {
+		t.Errorf("Expected 5 and true, but got %d and %v", *n, *verbose)
+		t.Fail()
+	}
+}
+
+func TestValueGetters(t *testing.T) {
+	opt := sopt.New()
+	lvl := level("info")
+	opt.SetValue("", "l", "level", "Log level.", &lvl, false, nil)
+	if opt.GetString("level") != "info" {
+		t.Errorf("Expected default info, but got '%s'", opt.GetString("level"))
+		t.Fail()
+	}
+
+	err := opt.ParseArgs([]string{"-l", "debug"})
+	if err != nil {
+		t.Errorf("Expected no error, but got %s", err.Error())
+		t.FailNow()
+	}
+
+	if opt.GetString("level") != "debug" {
+		t.Errorf("Expected debug, but got '%s'", opt.GetString("level"))
+		t.Fail()
+	}
+}
+
+func TestBindValueDefault(t *testing.T) {
+	var cfg struct {
+		Addr ipValue `sopt:"a,addr" default:"10.0.0.1" help:"Listen address."`
+	}
+
+	opt := sopt.New()
+	err := opt.Bind(&cfg)
+	if err != nil {
+		t.Errorf("Expected no error, but got %s", err.Error())
+		t.FailNow()
+	}
+
+	err = opt.ParseArgs([]string{})
+	if err != nil {
+		t.Errorf("Expected no error, but got %s", err.Error())
+		t.FailNow()
+	}
+
+	if cfg.Addr.String() != "10.0.0.1" || opt.GetString("addr") != "10.0.0.1" {
+		t.Errorf("Expected default 10.0.0.1, but got %s and %s", cfg.Addr.String(), opt.GetString("addr"))
+		t.Fail()
+	}
+}